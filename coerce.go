@@ -0,0 +1,197 @@
+package gojsonq
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Coerce returns a copy of the result whose accessors convert between numeric strings, numbers,
+// booleans and RFC 3339 times. e.g: NewResult("42").Coerce().Int()
+func (r *Result) Coerce() *Result {
+	return &Result{value: r.value, option: r.option, coerce: true}
+}
+
+// mismatch describes a value which can not be coerced at all
+func mismatch(v interface{}) error {
+	return fmt.Errorf(errMessage, reflect.ValueOf(v).Kind())
+}
+
+// coerceFloat converts v to a float of the bit size
+func coerceFloat(v interface{}, bitSize int) (float64, error) {
+	var f float64
+	switch x := v.(type) {
+	case float64:
+		f = x
+	case bool:
+		if x {
+			f = 1
+		}
+	case string, json.Number:
+		s := strings.TrimSpace(fmt.Sprint(x))
+		var err error
+		if f, err = strconv.ParseFloat(s, bitSize); err != nil {
+			return 0, coercionError(v, fmt.Sprintf("float%d", bitSize), err)
+		}
+		return f, nil
+	default:
+		return 0, mismatch(v)
+	}
+	if bitSize == 32 && math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+		return 0, &CoercionError{Value: v, Type: "float32", Err: ErrOverflow}
+	}
+	return f, nil
+}
+
+// coerceInt converts v to a signed integer of the bit size, fractions are reported instead of truncated
+func coerceInt(v interface{}, bitSize int) (int64, error) {
+	typ := fmt.Sprintf("int%d", bitSize)
+	if s, ok := stringOf(v); ok {
+		if i, err := strconv.ParseInt(s, 10, bitSize); err == nil {
+			return i, nil
+		} else if ne := err.(*strconv.NumError); ne.Err == strconv.ErrRange {
+			return 0, &CoercionError{Value: v, Type: typ, Err: ErrOverflow}
+		}
+	}
+	f, err := coerceFloat(v, 64)
+	if err != nil {
+		return 0, coercionType(err, typ)
+	}
+	if f != math.Trunc(f) {
+		return 0, &CoercionError{Value: v, Type: typ, Err: ErrPrecision}
+	}
+	min, max := -math.Ldexp(1, bitSize-1), math.Ldexp(1, bitSize-1)
+	if f < min || f >= max {
+		return 0, &CoercionError{Value: v, Type: typ, Err: ErrOverflow}
+	}
+	return int64(f), nil
+}
+
+// coerceUint converts v to an unsigned integer of the bit size, fractions are reported instead of truncated
+func coerceUint(v interface{}, bitSize int) (uint64, error) {
+	typ := fmt.Sprintf("uint%d", bitSize)
+	if s, ok := stringOf(v); ok {
+		if u, err := strconv.ParseUint(s, 10, bitSize); err == nil {
+			return u, nil
+		} else if ne := err.(*strconv.NumError); ne.Err == strconv.ErrRange {
+			return 0, &CoercionError{Value: v, Type: typ, Err: ErrOverflow}
+		}
+	}
+	f, err := coerceFloat(v, 64)
+	if err != nil {
+		return 0, coercionType(err, typ)
+	}
+	if f != math.Trunc(f) {
+		return 0, &CoercionError{Value: v, Type: typ, Err: ErrPrecision}
+	}
+	if f < 0 || f >= math.Ldexp(1, bitSize) {
+		return 0, &CoercionError{Value: v, Type: typ, Err: ErrOverflow}
+	}
+	return uint64(f), nil
+}
+
+// coerceBool converts v to a boolean, only 0 and 1 are accepted among the numbers
+func coerceBool(v interface{}) (bool, error) {
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(x))
+		if err != nil {
+			return false, coercionError(v, "bool", err)
+		}
+		return b, nil
+	case float64:
+		if x != 0 && x != 1 {
+			return false, coercionError(v, "bool", fmt.Errorf("%v is neither 0 nor 1", x))
+		}
+		return x == 1, nil
+	}
+	return false, mismatch(v)
+}
+
+// coerceString converts v to a string, numbers are formatted without exponent
+func coerceString(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case json.Number:
+		return string(x), nil
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	}
+	return "", mismatch(v)
+}
+
+// coerceTime converts v to a time, strings are parsed with the layout then as RFC 3339 and
+// numbers are Unix timestamps in seconds
+func coerceTime(v interface{}, layout string) (time.Time, error) {
+	switch x := v.(type) {
+	case string:
+		t, err := time.Parse(layout, x)
+		if err == nil {
+			return t, nil
+		}
+		if t, rerr := time.Parse(time.RFC3339Nano, x); rerr == nil {
+			return t, nil
+		}
+		return time.Time{}, coercionError(v, "time.Time", err)
+	case float64:
+		sec, frac := math.Modf(x)
+		if math.Abs(sec) > float64(math.MaxInt64/int64(time.Second)) {
+			return time.Time{}, &CoercionError{Value: v, Type: "time.Time", Err: ErrOverflow}
+		}
+		return time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC(), nil
+	}
+	return time.Time{}, mismatch(v)
+}
+
+// stringOf returns the trimmed numeric string of v
+func stringOf(v interface{}) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return strings.TrimSpace(x), true
+	case json.Number:
+		return string(x), true
+	}
+	return "", false
+}
+
+// coercionError describes a parsing failure, the range errors of strconv are reported as ErrOverflow
+func coercionError(v interface{}, typ string, err error) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		err = ne.Err
+		if err == strconv.ErrRange {
+			err = ErrOverflow
+		}
+	}
+	return &CoercionError{Value: v, Type: typ, Err: err}
+}
+
+// coercionType sets the target type of a CoercionError
+func coercionType(err error, typ string) error {
+	if ce, ok := err.(*CoercionError); ok {
+		ce.Type = typ
+	}
+	return err
+}
+
+// coerceEach calls fn for every element of the list, the index of the failing element is reported
+func (r *Result) coerceEach(fn func(v interface{}) error) error {
+	vv, ok := r.value.([]interface{})
+	if !ok {
+		return mismatch(r.value)
+	}
+	for i, v := range vv {
+		if err := fn(v); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
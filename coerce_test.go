@@ -0,0 +1,116 @@
+package gojsonq
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResult_Coerce(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		fn       func(r *Result) (interface{}, error)
+		value    interface{}
+		expected interface{}
+		err      error // expected error, errAny for any error
+	}{
+		{tag: "numeric string to int", fn: func(r *Result) (interface{}, error) { return r.Int() }, value: " 42", expected: 42},
+		{tag: "float string to int", fn: func(r *Result) (interface{}, error) { return r.Int() }, value: "42.0", expected: 42},
+		{tag: "bool to int", fn: func(r *Result) (interface{}, error) { return r.Int() }, value: true, expected: 1},
+		{tag: "fraction to int", fn: func(r *Result) (interface{}, error) { return r.Int() }, value: 1.5, err: ErrPrecision},
+		{tag: "fraction string to int", fn: func(r *Result) (interface{}, error) { return r.Int() }, value: "1.5", err: ErrPrecision},
+		{tag: "int8 overflow", fn: func(r *Result) (interface{}, error) { return r.Int8() }, value: float64(128), expected: int8(0), err: ErrOverflow},
+		{tag: "int8 string overflow", fn: func(r *Result) (interface{}, error) { return r.Int8() }, value: "-129", err: ErrOverflow},
+		{tag: "int8 min", fn: func(r *Result) (interface{}, error) { return r.Int8() }, value: float64(-128), expected: int8(-128)},
+		{tag: "big int64 string", fn: func(r *Result) (interface{}, error) { return r.Int64() }, value: "9223372036854775807", expected: int64(9223372036854775807)},
+		{tag: "negative to uint", fn: func(r *Result) (interface{}, error) { return r.Uint() }, value: float64(-1), err: ErrOverflow},
+		{tag: "uint16 string", fn: func(r *Result) (interface{}, error) { return r.Uint16() }, value: "65535", expected: uint16(65535)},
+		{tag: "uint8 overflow", fn: func(r *Result) (interface{}, error) { return r.Uint8() }, value: "256", err: ErrOverflow},
+		{tag: "invalid number", fn: func(r *Result) (interface{}, error) { return r.Int() }, value: "abc", err: errAny},
+		{tag: "float32 string", fn: func(r *Result) (interface{}, error) { return r.Float32() }, value: "1.5", expected: float32(1.5)},
+		{tag: "float32 overflow", fn: func(r *Result) (interface{}, error) { return r.Float32() }, value: 1e300, err: ErrOverflow},
+		{tag: "float64 string overflow", fn: func(r *Result) (interface{}, error) { return r.Float64() }, value: "1e400", err: ErrOverflow},
+		{tag: "string to bool", fn: func(r *Result) (interface{}, error) { return r.Bool() }, value: "true", expected: true},
+		{tag: "one to bool", fn: func(r *Result) (interface{}, error) { return r.Bool() }, value: float64(1), expected: true},
+		{tag: "two to bool", fn: func(r *Result) (interface{}, error) { return r.Bool() }, value: float64(2), err: errAny},
+		{tag: "number to string", fn: func(r *Result) (interface{}, error) { return r.String() }, value: 1e21, expected: "1000000000000000000000"},
+		{tag: "bool to string", fn: func(r *Result) (interface{}, error) { return r.String() }, value: false, expected: "false"},
+		{tag: "list to int", fn: func(r *Result) (interface{}, error) { return r.Int() }, value: []interface{}{}, err: errAny},
+		{tag: "RFC 3339 time", fn: func(r *Result) (interface{}, error) { return r.Time(time.Kitchen) }, value: "2019-01-02T15:04:05Z", expected: time.Date(2019, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{tag: "unix time", fn: func(r *Result) (interface{}, error) { return r.Time(time.RFC3339) }, value: float64(1546441445), expected: time.Date(2019, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{tag: "invalid time", fn: func(r *Result) (interface{}, error) { return r.Time(time.RFC3339) }, value: "yesterday", err: errAny},
+		{tag: "int slice", fn: func(r *Result) (interface{}, error) { return r.IntSlice() }, value: []interface{}{"1", float64(2), true}, expected: []int{1, 2, 1}},
+		{tag: "int slice error", fn: func(r *Result) (interface{}, error) { return r.Int8Slice() }, value: []interface{}{"1", "300"}, err: ErrOverflow},
+		{tag: "bool slice", fn: func(r *Result) (interface{}, error) { return r.BoolSlice() }, value: []interface{}{"false", float64(1)}, expected: []bool{false, true}},
+		{tag: "string slice", fn: func(r *Result) (interface{}, error) { return r.StringSlice() }, value: []interface{}{"a", float64(1.5)}, expected: []string{"a", "1.5"}},
+		{tag: "float64 slice", fn: func(r *Result) (interface{}, error) { return r.Float64Slice() }, value: []interface{}{"1.5", float64(2)}, expected: []float64{1.5, 2}},
+		{tag: "uint64 slice", fn: func(r *Result) (interface{}, error) { return r.Uint64Slice() }, value: []interface{}{"18446744073709551615"}, expected: []uint64{18446744073709551615}},
+		{tag: "slice of non list", fn: func(r *Result) (interface{}, error) { return r.IntSlice() }, value: "1", err: errAny},
+	}
+
+	for _, tc := range testCases {
+		v, err := tc.fn(NewResult(tc.value).Coerce())
+		switch {
+		case tc.err == errAny:
+			if err == nil {
+				t.Errorf("%s: expected an error, got %v", tc.tag, v)
+			}
+		case tc.err != nil:
+			if !errors.Is(err, tc.err) {
+				t.Errorf("%s: expected %v, got %v", tc.tag, tc.err, err)
+			}
+		case err != nil:
+			t.Errorf("%s: unexpected error: %v", tc.tag, err)
+		default:
+			assertInterface(t, tc.expected, v, tc.tag)
+		}
+	}
+}
+
+// errAny expects any error
+var errAny = errors.New("any error")
+
+func TestResult_Coerce_error_message(t *testing.T) {
+	_, err := NewResult([]interface{}{float64(1), "x"}).Coerce().IntSlice()
+	var ce *CoercionError
+	if !errors.As(err, &ce) || ce.Value != "x" {
+		t.Fatalf("expected a CoercionError, got: %v", err)
+	}
+	assertInterface(t, `[1]: cannot coerce x to int64: invalid syntax`, err.Error(), "coercion error message")
+}
+
+func TestResult_Coerce_with_option(t *testing.T) {
+	json := `{"user": {"id": "42", "active": "true", "scores": ["1", 2]}}`
+	r, err := New(WithCoercion()).FromString(json).FindR("user")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id, err := r.Find("id")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var n int
+	if err := id.As(&n); err != nil || n != 42 {
+		t.Errorf("failed As with coercion: %v %v", n, err)
+	}
+	active, err := r.Find("active")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b, err := active.Bool(); err != nil || !b {
+		t.Errorf("failed Bool with coercion: %v %v", b, err)
+	}
+	scores, err := r.Find("scores")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ii, err := scores.IntSlice(); err != nil || len(ii) != 2 {
+		t.Errorf("failed IntSlice with coercion: %v %v", ii, err)
+	}
+
+	// without coercion the strings are not converted
+	r, _ = New().FromString(json).FindR("user.id")
+	if _, err := r.Int(); err == nil {
+		t.Error("unexpected coercion")
+	}
+}
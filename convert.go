@@ -0,0 +1,558 @@
+package gojsonq
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ConvertError describes a value which can not be assigned to the target, e.g: users[3].age: cannot assign string to int
+type ConvertError struct {
+	Path  string       // path of the value in the result, empty for the result itself
+	Value interface{}  // value failed to assign
+	Type  reflect.Type // type of the target
+	Err   error        // *TypeError or the error of the json.Unmarshaler/encoding.TextUnmarshaler of the target
+}
+
+func (e *ConvertError) Error() string {
+	msg := e.Err.Error()
+	if _, ok := e.Err.(*TypeError); ok {
+		msg = fmt.Sprintf("cannot assign %s to %s", jsonType(e.Value), e.Type)
+	}
+	if e.Path == "" {
+		return msg
+	}
+	return e.Path + ": " + msg
+}
+
+// Unwrap returns the underlying error
+func (e *ConvertError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports a type mismatch as ErrTypeMismatch
+func (e *ConvertError) Is(target error) bool {
+	_, ok := e.Err.(*TypeError)
+	return ok && target == ErrTypeMismatch
+}
+
+// jsonType returns the json type name of the decoded value v
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}, map[string][]interface{}:
+		return "object"
+	}
+	if _, ok := toFloat64(v); ok {
+		return "number"
+	}
+	if _, ok := v.(json.Number); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// atPath prepends the segment of the path to the path of a ConvertError
+func atPath(err error, segment string) error {
+	if ce, ok := err.(*ConvertError); ok {
+		if ce.Path == "" || strings.HasPrefix(ce.Path, "[") {
+			ce.Path = segment + ce.Path
+		} else {
+			ce.Path = segment + "." + ce.Path
+		}
+	}
+	return err
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// convert assigns the decoded json value v to rv following the rules of encoding/json,
+// without encoding v back to json. Object keys are matched with the json tag or the field name
+func convert(v interface{}, rv reflect.Value) error {
+	if v == nil {
+		switch rv.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			rv.Set(reflect.Zero(rv.Type()))
+		}
+		return nil
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return convert(v, rv.Elem())
+	}
+	if ok, err := unmarshal(v, rv); ok {
+		return err
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		vv := reflect.ValueOf(copyValue(v))
+		if !vv.Type().Implements(rv.Type()) {
+			return convertError(v, rv)
+		}
+		rv.Set(vv)
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return convertError(v, rv)
+		}
+		rv.SetBool(b)
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return convertError(v, rv)
+		}
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, ok := v.(json.Number); ok {
+			i, err := strconv.ParseInt(string(n), 10, 64)
+			if err != nil || rv.OverflowInt(i) {
+				return convertError(v, rv)
+			}
+			rv.SetInt(i)
+			return nil
+		}
+		// the range is checked before converting, the conversion of a float out of range is undefined
+		f, ok := toFloat64(v)
+		bound := math.Ldexp(1, rv.Type().Bits()-1)
+		if !ok || f != math.Trunc(f) || f < -bound || f >= bound {
+			return convertError(v, rv)
+		}
+		rv.SetInt(int64(f))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n, ok := v.(json.Number); ok {
+			u, err := strconv.ParseUint(string(n), 10, 64)
+			if err != nil || rv.OverflowUint(u) {
+				return convertError(v, rv)
+			}
+			rv.SetUint(u)
+			return nil
+		}
+		f, ok := toFloat64(v)
+		if !ok || f < 0 || f != math.Trunc(f) || f >= math.Ldexp(1, rv.Type().Bits()) {
+			return convertError(v, rv)
+		}
+		rv.SetUint(uint64(f))
+	case reflect.Float32, reflect.Float64:
+		f, ok := toFloat64(v)
+		if n, isNumber := v.(json.Number); isNumber {
+			var err error
+			f, err = n.Float64()
+			ok = err == nil
+		}
+		if !ok || rv.OverflowFloat(f) {
+			return convertError(v, rv)
+		}
+		rv.SetFloat(f)
+	case reflect.Slice:
+		if s, ok := v.(string); ok && rv.Type().Elem().Kind() == reflect.Uint8 {
+			// a byte slice is encoded as a base64 string
+			b, err := base64.StdEncoding.DecodeString(s)
+			if err != nil {
+				return &ConvertError{Value: v, Type: rv.Type(), Err: err}
+			}
+			rv.SetBytes(b)
+			return nil
+		}
+		vv, ok := v.([]interface{})
+		if !ok {
+			return convertError(v, rv)
+		}
+		sv := reflect.MakeSlice(rv.Type(), len(vv), len(vv))
+		for i, e := range vv {
+			if err := convert(e, sv.Index(i)); err != nil {
+				return atPath(err, indexSegment(i))
+			}
+		}
+		rv.Set(sv)
+	case reflect.Array:
+		vv, ok := v.([]interface{})
+		if !ok {
+			return convertError(v, rv)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if i >= len(vv) {
+				rv.Index(i).Set(reflect.Zero(rv.Type().Elem()))
+				continue
+			}
+			if err := convert(vv[i], rv.Index(i)); err != nil {
+				return atPath(err, indexSegment(i))
+			}
+		}
+	case reflect.Map:
+		return convertMap(v, rv)
+	case reflect.Struct:
+		return convertStruct(v, rv)
+	default:
+		return convertError(v, rv)
+	}
+	return nil
+}
+
+// unmarshal assigns v using the json.Unmarshaler or the encoding.TextUnmarshaler (for strings) of rv,
+// ok is false when rv implements none of them
+func unmarshal(v interface{}, rv reflect.Value) (ok bool, err error) {
+	if !rv.CanAddr() {
+		return false, nil
+	}
+	pv := rv.Addr()
+	switch {
+	case pv.Type().Implements(unmarshalerType):
+		// the only case the value has to be encoded back to json
+		data, err := json.Marshal(v)
+		if err == nil {
+			err = pv.Interface().(json.Unmarshaler).UnmarshalJSON(data)
+		}
+		if err != nil {
+			return true, &ConvertError{Value: v, Type: rv.Type(), Err: err}
+		}
+		return true, nil
+	case pv.Type().Implements(textUnmarshalerType):
+		s, isString := v.(string)
+		if !isString {
+			return false, nil
+		}
+		if err := pv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return true, &ConvertError{Value: v, Type: rv.Type(), Err: err}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// convertMap assigns an object to a map, the keys are converted like encoding/json does:
+// using the encoding.TextUnmarshaler of the key type, or as string, integer or unsigned integer
+func convertMap(v interface{}, rv reflect.Value) error {
+	m, ok := objectOf(v)
+	kt := rv.Type().Key()
+	if !ok || !validMapKey(kt) {
+		return convertError(v, rv)
+	}
+	if rv.IsNil() {
+		rv.Set(reflect.MakeMapWithSize(rv.Type(), len(m)))
+	}
+	et := rv.Type().Elem()
+	for k, e := range m {
+		kv, err := mapKey(k, kt)
+		if err != nil {
+			return atPath(err, k)
+		}
+		ev := reflect.New(et).Elem()
+		if err := convert(e, ev); err != nil {
+			return atPath(err, k)
+		}
+		rv.SetMapIndex(kv, ev)
+	}
+	return nil
+}
+
+// validMapKey checks whether an object can be assigned to a map with keys of type kt
+func validMapKey(kt reflect.Type) bool {
+	if reflect.PtrTo(kt).Implements(textUnmarshalerType) {
+		return true
+	}
+	switch kt.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+// mapKey converts the object key k to the key type kt
+func mapKey(k string, kt reflect.Type) (reflect.Value, error) {
+	if reflect.PtrTo(kt).Implements(textUnmarshalerType) {
+		kv := reflect.New(kt)
+		if err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(k)); err != nil {
+			return reflect.Value{}, &ConvertError{Value: k, Type: kt, Err: err}
+		}
+		return kv.Elem(), nil
+	}
+	kv := reflect.New(kt).Elem()
+	switch kt.Kind() {
+	case reflect.String:
+		kv.SetString(k)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(k, 10, 64)
+		if err != nil || kv.OverflowInt(i) {
+			return reflect.Value{}, &ConvertError{Value: k, Type: kt, Err: &TypeError{Value: k, Expected: kt.String()}}
+		}
+		kv.SetInt(i)
+	default:
+		u, err := strconv.ParseUint(k, 10, 64)
+		if err != nil || kv.OverflowUint(u) {
+			return reflect.Value{}, &ConvertError{Value: k, Type: kt, Err: &TypeError{Value: k, Expected: kt.String()}}
+		}
+		kv.SetUint(u)
+	}
+	return kv, nil
+}
+
+// convertStruct assigns an object to a struct, unknown keys are ignored
+func convertStruct(v interface{}, rv reflect.Value) error {
+	m, ok := objectOf(v)
+	if !ok {
+		return convertError(v, rv)
+	}
+	fields := structFields(rv.Type())
+	for k, e := range m {
+		f, ok := fields.lookup(k)
+		if !ok {
+			continue
+		}
+		fv, ok := fieldByIndex(rv, f.index, e != nil)
+		if !ok {
+			continue
+		}
+		if f.quoted {
+			e = unquote(e, fv)
+		}
+		if err := convert(e, fv); err != nil {
+			return atPath(err, k)
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the field of the struct at index, the embedded struct pointers along the
+// index are allocated only when alloc is true, ok is false for a nil embedded struct pointer
+func fieldByIndex(rv reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !alloc || !rv.CanSet() {
+					return reflect.Value{}, false
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// unquote returns the value of a string encoded number or bool of a field tagged with the string option
+func unquote(e interface{}, fv reflect.Value) interface{} {
+	s, ok := e.(string)
+	if !ok {
+		return e
+	}
+	k := fv.Kind()
+	if k == reflect.Ptr {
+		k = fv.Type().Elem().Kind()
+	}
+	switch k {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return json.Number(s)
+	}
+	return e
+}
+
+// objectOf returns v as an object, grouped data is an object of lists
+func objectOf(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[string][]interface{}:
+		mm := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			mm[k] = e
+		}
+		return mm, true
+	}
+	return nil, false
+}
+
+// copyValue returns a deep copy of the objects and lists of v, so that the target never shares them with the content
+func copyValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			m[k] = copyValue(e)
+		}
+		return m
+	case map[string][]interface{}:
+		m := make(map[string][]interface{}, len(t))
+		for k, e := range t {
+			m[k] = copyValue(e).([]interface{})
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(t))
+		for i, e := range t {
+			l[i] = copyValue(e)
+		}
+		return l
+	}
+	return v
+}
+
+// convertError describes a value which can not be assigned to rv
+func convertError(v interface{}, rv reflect.Value) error {
+	return &ConvertError{Value: v, Type: rv.Type(), Err: &TypeError{Value: v, Expected: rv.Type().String()}}
+}
+
+// field describes an exported field of a struct, possibly promoted from an embedded struct
+type field struct {
+	name   string
+	index  []int
+	tagged bool // named by a json tag
+	quoted bool // tagged with the string option
+}
+
+// fieldList describes the fields of a struct
+type fieldList []field
+
+// lookup returns the field of the key, the exact name is preferred to a case insensitive match
+func (fl fieldList) lookup(key string) (field, bool) {
+	for _, f := range fl {
+		if f.name == key {
+			return f, true
+		}
+	}
+	for _, f := range fl {
+		if strings.EqualFold(f.name, key) {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// fieldCache caches the fields of the struct types
+var fieldCache sync.Map // map[reflect.Type]fieldList
+
+// structFields returns the fields of the struct type t named by their json tag or their name.
+// The fields of the embedded structs are promoted following the rules of encoding/json:
+// the shallowest field wins, then the tagged one, and conflicting fields are ignored
+func structFields(t reflect.Type) fieldList {
+	if fl, ok := fieldCache.Load(t); ok {
+		return fl.(fieldList)
+	}
+
+	// candidates by name, collected depth by depth
+	byName := map[string][]field{}
+	var names []string
+	type level struct {
+		t     reflect.Type
+		index []int
+	}
+	current := []level{{t: t}}
+	visited := map[reflect.Type]bool{}
+	for depth := 0; len(current) > 0; depth++ {
+		var next []level
+		found := map[string][]field{}
+		for _, l := range current {
+			if visited[l.t] {
+				continue
+			}
+			visited[l.t] = true
+			for i := 0; i < l.t.NumField(); i++ {
+				sf := l.t.Field(i)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := tag, ""
+				if i := strings.Index(tag, ","); i >= 0 {
+					name, opts = tag[:i], tag[i+1:]
+				}
+				index := append(append(make([]int, 0, len(l.index)+1), l.index...), i)
+
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					next = append(next, level{t: ft, index: index})
+					continue
+				}
+				if sf.PkgPath != "" {
+					continue // unexported
+				}
+				f := field{name: sf.Name, index: index, tagged: name != ""}
+				if name != "" {
+					f.name = name
+				}
+				for _, o := range strings.Split(opts, ",") {
+					f.quoted = f.quoted || o == "string"
+				}
+				found[f.name] = append(found[f.name], f)
+			}
+		}
+		for name, ff := range found {
+			if _, ok := byName[name]; ok {
+				continue // a shallower field wins
+			}
+			byName[name] = ff
+			names = append(names, name)
+		}
+		current = next
+	}
+
+	fl := make(fieldList, 0, len(names))
+	for _, name := range names {
+		ff := byName[name]
+		if len(ff) > 1 {
+			var tagged []field
+			for _, f := range ff {
+				if f.tagged {
+					tagged = append(tagged, f)
+				}
+			}
+			if len(tagged) != 1 {
+				continue // conflicting fields are ignored
+			}
+			ff = tagged
+		}
+		fl = append(fl, ff[0])
+	}
+	// fields in declaration order, so that the case insensitive match is deterministic
+	sortFields(fl)
+	fieldCache.Store(t, fl)
+	return fl
+}
+
+// sortFields sorts the fields by their index
+func sortFields(fl fieldList) {
+	less := func(a, b []int) bool {
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if a[i] != b[i] {
+				return a[i] < b[i]
+			}
+		}
+		return len(a) < len(b)
+	}
+	for i := 1; i < len(fl); i++ {
+		for k := i; k > 0 && less(fl[k].index, fl[k-1].index); k-- {
+			fl[k], fl[k-1] = fl[k-1], fl[k]
+		}
+	}
+}
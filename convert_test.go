@@ -0,0 +1,294 @@
+package gojsonq
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	type inner struct {
+		Name string
+	}
+	type target struct {
+		ID      int               `json:"id"`
+		Skipped string            `json:"-"`
+		Inner   inner             `json:"inner"`
+		Tags    []string          `json:"tags"`
+		Pair    [2]uint8          `json:"pair"`
+		Attrs   map[string]int    `json:"attrs"`
+		Ptr     *float32          `json:"ptr"`
+		Any     interface{}       `json:"any"`
+		Groups  map[string][]bool `json:"groups"`
+		private int
+	}
+
+	v := map[string]interface{}{
+		"id":      float64(7),
+		"Skipped": "no",
+		"inner":   map[string]interface{}{"name": "case insensitive"},
+		"tags":    []interface{}{"a", "b"},
+		"pair":    []interface{}{float64(1), float64(2), float64(3)},
+		"attrs":   map[string]interface{}{"x": float64(1)},
+		"ptr":     1.5,
+		"any":     []interface{}{"raw"},
+		"groups":  map[string][]interface{}{"g": {true}},
+		"private": float64(1),
+		"unknown": "ignored",
+	}
+	var out target
+	if err := convert(v, reflect.ValueOf(&out).Elem()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f := float32(1.5)
+	expected := target{
+		ID:     7,
+		Inner:  inner{Name: "case insensitive"},
+		Tags:   []string{"a", "b"},
+		Pair:   [2]uint8{1, 2},
+		Attrs:  map[string]int{"x": 1},
+		Ptr:    &f,
+		Any:    []interface{}{"raw"},
+		Groups: map[string][]bool{"g": {true}},
+	}
+	assertInterface(t, out, expected, "convert struct")
+
+	// null resets pointers, maps, slices and interfaces and leaves the other values untouched
+	out.ID = 3
+	if err := convert(map[string]interface{}{"id": nil, "ptr": nil, "tags": nil}, reflect.ValueOf(&out).Elem()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if out.ID != 3 || out.Ptr != nil || out.Tags != nil {
+		t.Errorf("unexpected null conversion: %+v", out)
+	}
+}
+
+func TestConvert_errors(t *testing.T) {
+	testCases := []struct {
+		tag    string
+		value  interface{}
+		target interface{}
+	}{
+		{tag: "string to int", value: "1", target: new(int)},
+		{tag: "fraction to int", value: 1.5, target: new(int)},
+		{tag: "overflow", value: float64(300), target: new(uint8)},
+		{tag: "negative to uint", value: float64(-1), target: new(uint)},
+		{tag: "int64 overflow", value: 1e20, target: new(int64)},
+		{tag: "int64 overflow at 2^63", value: 9223372036854775808.0, target: new(int64)},
+		{tag: "uint64 overflow", value: 1e20, target: new(uint64)},
+		{tag: "int overflow", value: -1e19, target: new(int)},
+		{tag: "infinity to int", value: math.Inf(1), target: new(int64)},
+		{tag: "float32 overflow", value: 1e300, target: new(float32)},
+		{tag: "number to bool", value: float64(1), target: new(bool)},
+		{tag: "number to string", value: float64(1), target: new(string)},
+		{tag: "object to slice", value: map[string]interface{}{}, target: new([]int)},
+		{tag: "list to array", value: "a", target: new([1]int)},
+		{tag: "invalid map key type", value: map[string]interface{}{}, target: new(map[bool]int)},
+		{tag: "non numeric map key", value: map[string]interface{}{"a": float64(1)}, target: new(map[int]int)},
+		{tag: "map key overflow", value: map[string]interface{}{"300": float64(1)}, target: new(map[uint8]int)},
+		{tag: "invalid base64", value: "not base64!", target: new([]byte)},
+		{tag: "list to struct", value: []interface{}{}, target: new(struct{})},
+		{tag: "interface", value: float64(1), target: new(error)},
+		{tag: "channel", value: float64(1), target: new(chan int)},
+		{tag: "nested", value: []interface{}{"a"}, target: new([]int)},
+	}
+	for _, tc := range testCases {
+		if err := convert(tc.value, reflect.ValueOf(tc.target).Elem()); err == nil {
+			t.Errorf("failed to catch %s", tc.tag)
+		}
+	}
+}
+
+type upperText string
+
+func (u *upperText) UnmarshalText(b []byte) error {
+	*u = upperText(strings.ToUpper(string(b)))
+	return nil
+}
+
+type rawJSON string
+
+func (r *rawJSON) UnmarshalJSON(b []byte) error {
+	*r = rawJSON(b)
+	return nil
+}
+
+func TestConvert_struct_features(t *testing.T) {
+	type base struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type Extra struct {
+		Name string `json:"name"`
+		Note string
+	}
+	type target struct {
+		base
+		*Extra
+		Name   string    `json:"name,omitempty"`
+		Code   upperText `json:"code"`
+		Raw    rawJSON   `json:"raw"`
+		Count  int64     `json:"count,string"`
+		Amount uint64    `json:"amount"`
+	}
+
+	v := map[string]interface{}{
+		"id":     float64(1),
+		"name":   "outer",
+		"Note":   "promoted",
+		"code":   "abc",
+		"raw":    map[string]interface{}{"a": float64(1)},
+		"count":  "42",
+		"amount": json.Number("18446744073709551615"),
+	}
+	var out target
+	if err := convert(v, reflect.ValueOf(&out).Elem()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := target{
+		base:   base{ID: 1},
+		Name:   "outer",
+		Code:   "ABC",
+		Raw:    `{"a":1}`,
+		Count:  42,
+		Amount: 18446744073709551615,
+	}
+	if out.Extra == nil || *out.Extra != (Extra{Note: "promoted"}) {
+		t.Errorf("unexpected embedded pointer: %+v", out.Extra)
+	}
+	out.Extra = nil
+	assertInterface(t, out, expected, "convert struct features")
+}
+
+func TestConvert_error_path(t *testing.T) {
+	type user struct {
+		Age int `json:"age"`
+	}
+	var out struct {
+		Users []user `json:"users"`
+	}
+	users := []interface{}{}
+	for i := 0; i < 3; i++ {
+		users = append(users, map[string]interface{}{"age": float64(i)})
+	}
+	users = append(users, map[string]interface{}{"age": "old"})
+
+	err := convert(map[string]interface{}{"users": users}, reflect.ValueOf(&out).Elem())
+	var ce *ConvertError
+	if !errors.As(err, &ce) || ce.Path != "users[3].age" {
+		t.Fatalf("expected a ConvertError at users[3].age, got: %v", err)
+	}
+	assertInterface(t, "users[3].age: cannot assign string to int", err.Error(), "convert error message")
+	var te *TypeError
+	if !errors.As(err, &te) {
+		t.Errorf("expected a TypeError, got: %v", err)
+	}
+}
+
+func TestJSONQ_Out_invalid_target(t *testing.T) {
+	var out []interface{}
+	jq := New().FromString(jsonStr).From("vendor.items")
+	jq.Out(out)
+	var te *TypeError
+	if !errors.As(jq.Error(), &te) {
+		t.Errorf("expected a TypeError, got: %v", jq.Error())
+	}
+}
+
+func TestConvert_integer_bounds(t *testing.T) {
+	var i8 int8
+	var i64 int64
+	var u64 uint64
+	if err := convert(float64(-128), reflect.ValueOf(&i8).Elem()); err != nil || i8 != -128 {
+		t.Errorf("failed to convert int8 min: %v %v", i8, err)
+	}
+	if err := convert(float64(-9223372036854775808), reflect.ValueOf(&i64).Elem()); err != nil || i64 != math.MinInt64 {
+		t.Errorf("failed to convert int64 min: %v %v", i64, err)
+	}
+	if err := convert(float64(1<<63), reflect.ValueOf(&u64).Elem()); err != nil || u64 != 1<<63 {
+		t.Errorf("failed to convert 2^63 to uint64: %v %v", u64, err)
+	}
+
+	var out struct {
+		N int64 `json:"n"`
+	}
+	jq := New().FromString(`{"n":1e20}`)
+	jq.Out(&out)
+	if jq.Error() == nil || out.N != 0 {
+		t.Errorf("failed to catch int64 overflow: %v %v", out.N, jq.Error())
+	}
+}
+
+// textKey is a map key implementing encoding.TextUnmarshaler
+type textKey struct {
+	a, b string
+}
+
+func (k *textKey) UnmarshalText(b []byte) error {
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 {
+		return errors.New("invalid key")
+	}
+	k.a, k.b = parts[0], parts[1]
+	return nil
+}
+
+func TestJSONQ_Out_same_as_json_Unmarshal(t *testing.T) {
+	type payload struct {
+		Data []byte            `json:"data"`
+		Sums map[int64]float64 `json:"sums"`
+	}
+	testCases := []struct {
+		tag    string
+		json   string
+		target func() interface{}
+	}{
+		{tag: "base64 to bytes", json: `{"data":"aGVsbG8="}`, target: func() interface{} { return new(payload) }},
+		{tag: "bytes as list", json: `[1,2,3]`, target: func() interface{} { return new([]byte) }},
+		{tag: "int keys", json: `{"1":"a","-2":"b"}`, target: func() interface{} { return new(map[int]string) }},
+		{tag: "uint keys", json: `{"1":"a","2":"b"}`, target: func() interface{} { return new(map[uint16]string) }},
+		{tag: "int64 keys in struct", json: `{"sums":{"10":1.5}}`, target: func() interface{} { return new(payload) }},
+		{tag: "text unmarshaler keys", json: `{"x:y":1,"a:b":2}`, target: func() interface{} { return new(map[textKey]int) }},
+		{tag: "named string keys", json: `{"k":true}`, target: func() interface{} { return new(map[upperText]bool) }},
+	}
+	for _, tc := range testCases {
+		expected, got := tc.target(), tc.target()
+		if err := json.Unmarshal([]byte(tc.json), expected); err != nil {
+			t.Fatalf("%s: json.Unmarshal: %v", tc.tag, err)
+		}
+		jq := New().FromString(tc.json)
+		jq.Out(got)
+		if jq.Error() != nil {
+			t.Errorf("%s: unexpected error: %v", tc.tag, jq.Error())
+			continue
+		}
+		if !reflect.DeepEqual(expected, got) {
+			t.Errorf("%s: expected %+v got: %+v", tc.tag, expected, got)
+		}
+	}
+}
+
+func TestJSONQ_Out_copies_the_content(t *testing.T) {
+	jq := New().FromString(`{"items":[{"id":1,"tags":["a","b"]}]}`)
+	var out []map[string]interface{}
+	jq.From("items").Out(&out)
+	out[0]["tags"].([]interface{})[0] = "x"
+	out[0]["id"] = 2
+	jq.Reset()
+	assertJSON(t, jq.Find("items"), `[{"id":1,"tags":["a","b"]}]`, "content after changing the result of Out")
+
+	doc, err := New().FromString(`[{"id":1}]`).Document()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var o interface{}
+	if err := doc.Query().Out(&o); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	o.([]interface{})[0].(map[string]interface{})["id"] = 99
+	out2, _ := doc.Query().Get()
+	assertJSON(t, out2, `[{"id":1}]`, "document after changing the result of Out")
+}
@@ -0,0 +1,67 @@
+package gojsonq
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+)
+
+// decompressPeekSize is the number of bytes peeked to detect the compression, the first byte of a
+// zlib stream is decompressed from them to tell it apart from content starting like a zlib header
+const decompressPeekSize = 4096
+
+// decompress returns a reader of the decompressed content of r when the content is compressed
+// with gzip, zlib or bzip2, detected by its magic bytes, otherwise a reader of the content as is
+func (j *JSONQ) decompress(r io.Reader) (io.Reader, error) {
+	if !j.option.decompression {
+		return r, nil
+	}
+	br := bufio.NewReaderSize(r, decompressPeekSize)
+	head, _ := br.Peek(decompressPeekSize)
+	switch {
+	case len(head) >= 2 && head[0] == 0x1f && head[1] == 0x8b:
+		return gzip.NewReader(br)
+	case isBzip2(head):
+		return bzip2.NewReader(br), nil
+	case isZlib(head):
+		return zlib.NewReader(br)
+	}
+	return br, nil
+}
+
+// bzip2 magic numbers of the first block and of the end of an empty stream
+var (
+	bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	bzip2EOSMagic   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// isBzip2 checks the bzip2 header: BZh, the block size and the magic of the first block
+func isBzip2(head []byte) bool {
+	if len(head) < 10 || !bytes.HasPrefix(head, []byte("BZh")) || head[3] < '1' || head[3] > '9' {
+		return false
+	}
+	return bytes.Equal(head[4:10], bzip2BlockMagic) || bytes.Equal(head[4:10], bzip2EOSMagic)
+}
+
+// isZlib checks the zlib header (deflate compression method and a valid check of the two bytes)
+// and confirms it by decompressing the first byte, as plain content may start like a zlib header
+// e.g: "80" or "x "
+func isZlib(head []byte) bool {
+	if len(head) < 2 {
+		return false
+	}
+	cmf, flg := head[0], head[1]
+	if cmf&0x0f != 8 || cmf>>4 > 7 || (uint16(cmf)<<8|uint16(flg))%31 != 0 {
+		return false
+	}
+	zr, err := zlib.NewReader(bytes.NewReader(head))
+	if err != nil {
+		return false
+	}
+	defer zr.Close()
+	_, err = zr.Read(make([]byte, 1))
+	return err == nil || err == io.EOF
+}
@@ -0,0 +1,124 @@
+package gojsonq
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// bzip2JSON is `{"name":"bzip2","items":[1,2,3]}` compressed with bzip2, the standard library has no bzip2 writer
+const bzip2JSON = "QlpoOTFBWSZTWWVZ48AAAA8bgBAEOBAACjIjTBogACIhpiNBkyFMJpoDTEFWQoNEy5gQIri/oF2tB1iZ+LuSKcKEgyrPHgA="
+
+func gzipData(t *testing.T, s string) []byte {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	return b.Bytes()
+}
+
+func zlibData(t *testing.T, s string) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	if _, err := w.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	return b.Bytes()
+}
+
+func TestJSONQ_Reader_decompression(t *testing.T) {
+	bz, _ := base64.StdEncoding.DecodeString(bzip2JSON)
+	testCases := []struct {
+		tag      string
+		data     []byte
+		path     string
+		expected string
+	}{
+		{tag: "gzip", data: gzipData(t, jsonStr), path: "vendor.name", expected: `"Star Trek"`},
+		{tag: "zlib", data: zlibData(t, jsonStr), path: "vendor.items.[0].id", expected: `1`},
+		{tag: "bzip2", data: bz, path: "name", expected: `"bzip2"`},
+		{tag: "plain", data: []byte(jsonStr), path: "vendor.name", expected: `"Star Trek"`},
+		{tag: "short plain", data: []byte(`[1]`), path: "[0]", expected: `1`},
+	}
+	for _, tc := range testCases {
+		jq := New().Reader(bytes.NewReader(tc.data))
+		if jq.Error() != nil {
+			t.Errorf("%s: unexpected error: %v", tc.tag, jq.Error())
+			continue
+		}
+		assertJSON(t, jq.Find(tc.path), tc.expected, tc.tag)
+	}
+}
+
+func TestJSONQ_File_decompression(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gojsonq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "export.json.gz")
+	if err := ioutil.WriteFile(name, gzipData(t, jsonStr), 0600); err != nil {
+		t.Fatal(err)
+	}
+	jq := New().File(name).From("vendor.items").Where("price", ">", 1200)
+	assertJSON(t, jq.Count(), `2`, "File of gzip content")
+}
+
+func TestJSONQ_Reader_decompression_errors(t *testing.T) {
+	// disabled detection decodes the compressed bytes as json
+	jq := New(WithDecompression(false)).Reader(bytes.NewReader(gzipData(t, jsonStr)))
+	var de *DecodeError
+	if !errors.As(jq.Error(), &de) {
+		t.Errorf("expected a DecodeError, got: %v", jq.Error())
+	}
+
+	// the maximum input size applies to the decompressed content
+	data := gzipData(t, `{"data":"`+strings.Repeat("a", 10000)+`"}`)
+	jq = New(WithMaxInputSize(1000)).Reader(bytes.NewReader(data))
+	if len(data) >= 1000 || !errors.Is(jq.Error(), ErrInputTooLarge) {
+		t.Errorf("expected ErrInputTooLarge for %d compressed bytes, got: %v", len(data), jq.Error())
+	}
+
+	// corrupted content
+	corrupted := gzipData(t, jsonStr)[:20]
+	if jq := New().Reader(bytes.NewReader(corrupted)); jq.Error() == nil {
+		t.Error("failed to catch corrupted gzip content")
+	}
+	if jq := New().Reader(bytes.NewReader([]byte{0x1f, 0x8b, 0})); jq.Error() == nil {
+		t.Error("failed to catch invalid gzip header")
+	}
+}
+
+func TestJSONQ_Reader_plain_content_like_a_compression_header(t *testing.T) {
+	// 0x3830 ("80") and 0x7820 ("x ") are valid zlib headers
+	for _, data := range []string{"800", "80.5", "8001", "[80]"} {
+		jq := New().Reader(strings.NewReader(data))
+		if jq.Error() != nil {
+			t.Errorf("%s: unexpected error: %v", data, jq.Error())
+			continue
+		}
+		var expected interface{}
+		New().FromString(data).Out(&expected)
+		assertInterface(t, expected, jq.Get(), data)
+	}
+
+	for _, data := range []string{"x ,y\n1,2", "H,a\n", "BZh9 text"} {
+		r, err := New().decompress(strings.NewReader(data))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", data, err)
+			continue
+		}
+		out, _ := ioutil.ReadAll(r)
+		assertInterface(t, data, string(out), "plain content read as is")
+	}
+}
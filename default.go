@@ -0,0 +1,301 @@
+package gojsonq
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNilResult is the panic value of the Must accessors called on a nil result, e.g: the result of FindR for a missing path
+var ErrNilResult = errors.New("gojsonq: result is nil")
+
+// The Or accessors return the default value when the result is nil, null or can not be asserted,
+// the Must accessors panic instead. They are safe to call on the nil result returned by FindR for a missing path.
+// e.g: r, _ := jq.FindR("server.port"); port := r.IntOr(8080)
+
+// StringOr returns the result as string or def
+func (r *Result) StringOr(def string) string {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.String()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustString returns the result as string, it panics on failure
+func (r *Result) MustString() string {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.String()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IntOr returns the result as int or def
+func (r *Result) IntOr(def int) int {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.Int()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustInt returns the result as int, it panics on failure
+func (r *Result) MustInt() int {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.Int()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// BoolOr returns the result as bool or def
+func (r *Result) BoolOr(def bool) bool {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.Bool()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustBool returns the result as bool, it panics on failure
+func (r *Result) MustBool() bool {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.Bool()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Float64Or returns the result as float64 or def
+func (r *Result) Float64Or(def float64) float64 {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.Float64()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustFloat64 returns the result as float64, it panics on failure
+func (r *Result) MustFloat64() float64 {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.Float64()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// TimeOr returns the result as time.Time or def
+func (r *Result) TimeOr(layout string, def time.Time) time.Time {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.Time(layout)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustTime returns the result as time.Time, it panics on failure
+func (r *Result) MustTime(layout string) time.Time {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.Time(layout)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// DurationOr returns the result as time.Duration or def
+func (r *Result) DurationOr(def time.Duration) time.Duration {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.Duration()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustDuration returns the result as time.Duration, it panics on failure
+func (r *Result) MustDuration() time.Duration {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.Duration()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// StringSliceOr returns the result as []string or def
+func (r *Result) StringSliceOr(def []string) []string {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.StringSlice()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustStringSlice returns the result as []string, it panics on failure
+func (r *Result) MustStringSlice() []string {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.StringSlice()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IntSliceOr returns the result as []int or def
+func (r *Result) IntSliceOr(def []int) []int {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.IntSlice()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustIntSlice returns the result as []int, it panics on failure
+func (r *Result) MustIntSlice() []int {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.IntSlice()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// BoolSliceOr returns the result as []bool or def
+func (r *Result) BoolSliceOr(def []bool) []bool {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.BoolSlice()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustBoolSlice returns the result as []bool, it panics on failure
+func (r *Result) MustBoolSlice() []bool {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.BoolSlice()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Float64SliceOr returns the result as []float64 or def
+func (r *Result) Float64SliceOr(def []float64) []float64 {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.Float64Slice()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustFloat64Slice returns the result as []float64, it panics on failure
+func (r *Result) MustFloat64Slice() []float64 {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.Float64Slice()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// TimeSliceOr returns the result as []time.Time or def
+func (r *Result) TimeSliceOr(layout string, def []time.Time) []time.Time {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.TimeSlice(layout)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustTimeSlice returns the result as []time.Time, it panics on failure
+func (r *Result) MustTimeSlice(layout string) []time.Time {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.TimeSlice(layout)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// DurationSliceOr returns the result as []time.Duration or def
+func (r *Result) DurationSliceOr(def []time.Duration) []time.Duration {
+	if r.Nil() {
+		return def
+	}
+	v, err := r.DurationSlice()
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// MustDurationSlice returns the result as []time.Duration, it panics on failure
+func (r *Result) MustDurationSlice() []time.Duration {
+	if r == nil {
+		panic(ErrNilResult)
+	}
+	v, err := r.DurationSlice()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -0,0 +1,79 @@
+package gojsonq
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResult_Or(t *testing.T) {
+	jq := New().FromString(`{"server": {"host": "localhost", "port": 8080, "debug": true, "ratio": 0.5,
+		"started": "2019-01-02T15:04:05Z", "timeout": "5s", "tags": ["a", "b"], "ports": [1, 2], "flags": [true],
+		"ratios": [0.5], "dates": ["2019-01-02T15:04:05Z"], "timeouts": ["1s"], "empty": null}}`)
+	find := func(path string) *Result {
+		r, _ := jq.Copy().FindR(path)
+		return r
+	}
+	date := time.Date(2019, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	assertInterface(t, "localhost", find("server.host").StringOr("x"), "StringOr")
+	assertInterface(t, 8080, find("server.port").IntOr(80), "IntOr")
+	assertInterface(t, true, find("server.debug").BoolOr(false), "BoolOr")
+	assertInterface(t, 0.5, find("server.ratio").Float64Or(1), "Float64Or")
+	assertInterface(t, date, find("server.started").TimeOr(time.RFC3339, time.Time{}), "TimeOr")
+	assertInterface(t, 5*time.Second, find("server.timeout").DurationOr(time.Second), "DurationOr")
+	assertInterface(t, []string{"a", "b"}, find("server.tags").StringSliceOr(nil), "StringSliceOr")
+	assertInterface(t, []int{1, 2}, find("server.ports").IntSliceOr(nil), "IntSliceOr")
+	assertInterface(t, []bool{true}, find("server.flags").BoolSliceOr(nil), "BoolSliceOr")
+	assertInterface(t, []float64{0.5}, find("server.ratios").Float64SliceOr(nil), "Float64SliceOr")
+	assertInterface(t, []time.Time{date}, find("server.dates").TimeSliceOr(time.RFC3339, nil), "TimeSliceOr")
+	assertInterface(t, []time.Duration{time.Second}, find("server.timeouts").DurationSliceOr(nil), "DurationSliceOr")
+
+	// missing paths, nulls and mismatched types fall back to the default value
+	missing := find("server.missing")
+	if missing != nil {
+		t.Fatalf("expected a nil result for a missing path, got %v", missing)
+	}
+	assertInterface(t, "x", missing.StringOr("x"), "StringOr of missing path")
+	assertInterface(t, 80, missing.IntOr(80), "IntOr of missing path")
+	assertInterface(t, []string{"d"}, missing.StringSliceOr([]string{"d"}), "StringSliceOr of missing path")
+	assertInterface(t, 80, find("server.empty").IntOr(80), "IntOr of null")
+	assertInterface(t, 80, find("server.host").IntOr(80), "IntOr of mismatched type")
+	assertInterface(t, date, find("server.host").TimeOr(time.RFC3339, date), "TimeOr of invalid time")
+	assertInterface(t, []int{3}, find("server.host").IntSliceOr([]int{3}), "IntSliceOr of mismatched type")
+}
+
+func TestResult_Must(t *testing.T) {
+	r := NewResult("localhost")
+	assertInterface(t, "localhost", r.MustString(), "MustString")
+	assertInterface(t, []int{1}, NewResult([]interface{}{float64(1)}).MustIntSlice(), "MustIntSlice")
+	assertInterface(t, time.Second, NewResult("1s").MustDuration(), "MustDuration")
+
+	testCases := []struct {
+		tag string
+		fn  func()
+		err error
+	}{
+		{tag: "nil result", fn: func() { (*Result)(nil).MustString() }, err: ErrNilResult},
+		{tag: "nil slice result", fn: func() { (*Result)(nil).MustTimeSlice(time.RFC3339) }, err: ErrNilResult},
+		{tag: "mismatched type", fn: func() { r.MustInt() }},
+		{tag: "null", fn: func() { NewResult(nil).MustBool() }},
+		{tag: "invalid time", fn: func() { r.MustTime(time.RFC3339) }},
+	}
+	for _, tc := range testCases {
+		func() {
+			defer func() {
+				rec := recover()
+				err, ok := rec.(error)
+				if !ok {
+					t.Errorf("%s: expected a panic with an error, got %v", tc.tag, rec)
+					return
+				}
+				if tc.err != nil && !errors.Is(err, tc.err) {
+					t.Errorf("%s: expected %v, got %v", tc.tag, tc.err, err)
+				}
+			}()
+			tc.fn()
+		}()
+	}
+}
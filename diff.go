@@ -0,0 +1,271 @@
+package gojsonq
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeType describes the kind of a Change
+type ChangeType string
+
+// Available change types
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// Change describes a single difference between two documents
+type Change struct {
+	Type ChangeType  // kind of the change
+	Path string      // path of the value e.g: "vendor.items.[0].price"
+	From interface{} // value in the first document, nil for added values
+	To   interface{} // value in the second document, nil for removed values
+
+	pointer []string // path segments used to build the JSON pointer
+	append  bool     // value is appended to an array compared without order
+}
+
+// diffOption describes the configuration of Diff
+type diffOption struct {
+	ignoreArrayOrder bool
+	ignorePaths      []string
+}
+
+// DiffOptionFunc represents a contract for diff option func, it basically set options to Diff
+type DiffOptionFunc func(*diffOption)
+
+// IgnoreArrayOrder compares arrays without considering the position of their elements
+func IgnoreArrayOrder() DiffOptionFunc {
+	return func(o *diffOption) {
+		o.ignoreArrayOrder = true
+	}
+}
+
+// IgnorePaths skips the provided paths and their children while comparing. e.g: IgnorePaths("meta.updated_at")
+func IgnorePaths(paths ...string) DiffOptionFunc {
+	return func(o *diffOption) {
+		o.ignorePaths = append(o.ignorePaths, paths...)
+	}
+}
+
+// Diff compares the query results of a and b and returns the changes required to turn a into b.
+// Paths are built using the separator of a. Errors occurred while querying remain available on a and b
+func Diff(a, b *JSONQ, options ...DiffOptionFunc) []Change {
+	d := &differ{separator: a.option.separator}
+	for _, option := range options {
+		option(&d.option)
+	}
+	d.compare(a.Get(), b.Get(), nil, nil)
+	return d.changes
+}
+
+// differ holds the state of a running comparison
+type differ struct {
+	option    diffOption
+	separator string
+	changes   []Change
+}
+
+// compare compares x with y and collects the changes found under the provided path
+func (d *differ) compare(x, y interface{}, path, pointer []string) {
+	if d.ignored(path) {
+		return
+	}
+	switch xv := x.(type) {
+	case map[string]interface{}:
+		if yv, ok := y.(map[string]interface{}); ok {
+			d.compareMap(xv, yv, path, pointer)
+			return
+		}
+	case []interface{}:
+		if yv, ok := y.([]interface{}); ok {
+			if d.option.ignoreArrayOrder {
+				d.compareUnorderedList(xv, yv, path, pointer)
+			} else {
+				d.compareList(xv, yv, path, pointer)
+			}
+			return
+		}
+	}
+	if !isEqual(x, y) {
+		d.add(ChangeChanged, x, y, path, pointer)
+	}
+}
+
+// compareMap compares two objects key by key in sorted order
+func (d *differ) compareMap(x, y map[string]interface{}, path, pointer []string) {
+	keys := make([]string, 0, len(x)+len(y))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	for k := range y {
+		if _, ok := x[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p, ptr := extend(path, k), extend(pointer, k)
+		xv, okX := x[k]
+		yv, okY := y[k]
+		switch {
+		case !okY:
+			if !d.ignored(p) {
+				d.add(ChangeRemoved, xv, nil, p, ptr)
+			}
+		case !okX:
+			if !d.ignored(p) {
+				d.add(ChangeAdded, nil, yv, p, ptr)
+			}
+		default:
+			d.compare(xv, yv, p, ptr)
+		}
+	}
+}
+
+// compareList compares two arrays position by position.
+// Removals are reported from the end of the array so that the changes can be applied in order
+func (d *differ) compareList(x, y []interface{}, path, pointer []string) {
+	for i := 0; i < len(x) && i < len(y); i++ {
+		d.compare(x[i], y[i], extend(path, indexSegment(i)), extend(pointer, strconv.Itoa(i)))
+	}
+	for i := len(x); i < len(y); i++ {
+		p := extend(path, indexSegment(i))
+		if !d.ignored(p) {
+			d.add(ChangeAdded, nil, y[i], p, extend(pointer, strconv.Itoa(i)))
+		}
+	}
+	for i := len(x) - 1; i >= len(y); i-- {
+		p := extend(path, indexSegment(i))
+		if !d.ignored(p) {
+			d.add(ChangeRemoved, x[i], nil, p, extend(pointer, strconv.Itoa(i)))
+		}
+	}
+}
+
+// compareUnorderedList compares two arrays as multisets. Elements without an equal
+// counterpart are reported as removed (by their index in x) or added (by their index in y)
+func (d *differ) compareUnorderedList(x, y []interface{}, path, pointer []string) {
+	matched := make([]bool, len(y))
+	var removed []int
+	for i, xv := range x {
+		found := false
+		for k, yv := range y {
+			if !matched[k] && isEqual(xv, yv) {
+				matched[k] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, i)
+		}
+	}
+	for n := len(removed) - 1; n >= 0; n-- {
+		i := removed[n]
+		p := extend(path, indexSegment(i))
+		if !d.ignored(p) {
+			d.add(ChangeRemoved, x[i], nil, p, extend(pointer, strconv.Itoa(i)))
+		}
+	}
+	for k, ok := range matched {
+		p := extend(path, indexSegment(k))
+		if !ok && !d.ignored(p) {
+			d.add(ChangeAdded, nil, y[k], p, extend(pointer, strconv.Itoa(k)))
+			d.changes[len(d.changes)-1].append = true
+		}
+	}
+}
+
+// add appends a new change to the list
+func (d *differ) add(typ ChangeType, from, to interface{}, path, pointer []string) {
+	d.changes = append(d.changes, Change{
+		Type:    typ,
+		Path:    strings.Join(path, d.separator),
+		From:    from,
+		To:      to,
+		pointer: pointer,
+	})
+}
+
+// ignored checks whether the path or one of its parents is ignored
+func (d *differ) ignored(path []string) bool {
+	if len(d.option.ignorePaths) == 0 || len(path) == 0 {
+		return false
+	}
+	p := strings.Join(path, d.separator)
+	for _, ip := range d.option.ignorePaths {
+		if p == ip || strings.HasPrefix(p, ip+d.separator) {
+			return true
+		}
+	}
+	return false
+}
+
+// extend returns a new path with the segment appended, the original path stays untouched
+func extend(path []string, segment string) []string {
+	p := make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, segment)
+}
+
+// indexSegment returns the path segment for an array index e.g: [2]
+func indexSegment(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// PatchOperation describes a JSON Patch (RFC 6902) operation
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON satisfies the json.Marshaler interface, remove operation does not carry any value
+func (p PatchOperation) MarshalJSON() ([]byte, error) {
+	if p.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{p.Op, p.Path})
+	}
+	type operation PatchOperation
+	return json.Marshal(operation(p))
+}
+
+// ToPatch converts the changes returned by Diff to JSON Patch (RFC 6902) operations.
+// Marshaling the returned list produces a valid JSON Patch document
+func ToPatch(changes []Change) []PatchOperation {
+	ops := make([]PatchOperation, 0, len(changes))
+	for _, c := range changes {
+		pointer := jsonPointer(c.pointer)
+		switch c.Type {
+		case ChangeAdded:
+			if c.append {
+				pointer = jsonPointer(append(c.pointer[:len(c.pointer)-1:len(c.pointer)-1], "-"))
+			}
+			ops = append(ops, PatchOperation{Op: "add", Path: pointer, Value: c.To})
+		case ChangeRemoved:
+			ops = append(ops, PatchOperation{Op: "remove", Path: pointer})
+		case ChangeChanged:
+			ops = append(ops, PatchOperation{Op: "replace", Path: pointer, Value: c.To})
+		}
+	}
+	return ops
+}
+
+// jsonPointer builds a JSON pointer (RFC 6901) from path segments
+func jsonPointer(segments []string) string {
+	var sb strings.Builder
+	for _, s := range segments {
+		sb.WriteString("/")
+		s = strings.Replace(s, "~", "~0", -1)
+		s = strings.Replace(s, "/", "~1", -1)
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
@@ -0,0 +1,114 @@
+package gojsonq
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		a, b     string
+		options  []DiffOptionFunc
+		expected string
+	}{
+		{
+			tag:      "equal documents",
+			a:        `{"name":"gojsonq","tags":["a","b"]}`,
+			b:        `{"tags":["a","b"],"name":"gojsonq"}`,
+			expected: `null`,
+		},
+		{
+			tag:      "added, removed and changed keys",
+			a:        `{"name":"gojsonq","port":80,"debug":true}`,
+			b:        `{"name":"gojsonq","port":8080,"env":"prod"}`,
+			expected: `[{"Type":"removed","Path":"debug","From":true,"To":null},{"Type":"added","Path":"env","From":null,"To":"prod"},{"Type":"changed","Path":"port","From":80,"To":8080}]`,
+		},
+		{
+			tag:      "nested objects and arrays",
+			a:        `{"db":{"hosts":["a","b","c"]}}`,
+			b:        `{"db":{"hosts":["a","x"]}}`,
+			expected: `[{"Type":"changed","Path":"db.hosts.[1]","From":"b","To":"x"},{"Type":"removed","Path":"db.hosts.[2]","From":"c","To":null}]`,
+		},
+		{
+			tag:      "type change",
+			a:        `{"db":{"host":"a"}}`,
+			b:        `{"db":["a"]}`,
+			expected: `[{"Type":"changed","Path":"db","From":{"host":"a"},"To":["a"]}]`,
+		},
+		{
+			tag:      "ignore array order",
+			a:        `{"hosts":["a","b","c"]}`,
+			b:        `{"hosts":["c","a","d"]}`,
+			options:  []DiffOptionFunc{IgnoreArrayOrder()},
+			expected: `[{"Type":"removed","Path":"hosts.[1]","From":"b","To":null},{"Type":"added","Path":"hosts.[2]","From":null,"To":"d"}]`,
+		},
+		{
+			tag:      "ignore paths",
+			a:        `{"meta":{"updated_at":"2020"},"name":"a","items":[{"id":1,"ts":1}]}`,
+			b:        `{"meta":{"updated_at":"2021"},"name":"b","items":[{"id":1,"ts":2}]}`,
+			options:  []DiffOptionFunc{IgnorePaths("meta", "items.[0].ts")},
+			expected: `[{"Type":"changed","Path":"name","From":"a","To":"b"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		changes := Diff(New().FromString(tc.a), New().FromString(tc.b), tc.options...)
+		assertJSON(t, changes, tc.expected, tc.tag)
+	}
+}
+
+func TestDiff_with_query(t *testing.T) {
+	a := New().FromString(jsonStr).From("vendor.items").WhereIn("id", []int{1, 2})
+	b := New().FromString(jsonStr).From("vendor.items").WhereIn("id", []int{1, 3})
+	expected := `[{"Type":"changed","Path":"[1].id","From":2,"To":3},{"Type":"changed","Path":"[1].name","From":"MacBook Pro 15 inch retina","To":"Sony VAIO"},{"Type":"changed","Path":"[1].price","From":1700,"To":1200}]`
+	assertJSON(t, Diff(a, b), expected, "diff between query results")
+}
+
+func TestDiff_with_custom_separator(t *testing.T) {
+	a := New(WithSeparator("->")).FromString(`{"user":{"name":"tom"}}`)
+	b := New().FromString(`{"user":{"name":"jerry"}}`)
+	changes := Diff(a, b)
+	if len(changes) != 1 || changes[0].Path != "user->name" {
+		t.Errorf("failed to build path using custom separator, got: %v", changes)
+	}
+}
+
+func TestToPatch(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		a, b     string
+		options  []DiffOptionFunc
+		expected string
+	}{
+		{
+			tag:      "add, remove and replace",
+			a:        `{"name":"gojsonq","port":80,"debug":true,"a/b":1}`,
+			b:        `{"name":"gojsonq","port":null,"env":"prod","a/b":2}`,
+			expected: `[{"op":"replace","path":"/a~1b","value":2},{"op":"remove","path":"/debug"},{"op":"add","path":"/env","value":"prod"},{"op":"replace","path":"/port","value":null}]`,
+		},
+		{
+			tag:      "arrays are shrunk from the end",
+			a:        `{"hosts":["a","b","c"]}`,
+			b:        `{"hosts":["a"]}`,
+			expected: `[{"op":"remove","path":"/hosts/2"},{"op":"remove","path":"/hosts/1"}]`,
+		},
+		{
+			tag:      "unordered additions are appended",
+			a:        `{"hosts":["a","b"]}`,
+			b:        `{"hosts":["c","a"]}`,
+			options:  []DiffOptionFunc{IgnoreArrayOrder()},
+			expected: `[{"op":"remove","path":"/hosts/1"},{"op":"add","path":"/hosts/-","value":"c"}]`,
+		},
+		{
+			tag:      "root replacement",
+			a:        `{"name":"a"}`,
+			b:        `["a"]`,
+			expected: `[{"op":"replace","path":"","value":["a"]}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		changes := Diff(New().FromString(tc.a), New().FromString(tc.b), tc.options...)
+		assertJSON(t, ToPatch(changes), tc.expected, tc.tag)
+	}
+}
@@ -0,0 +1,309 @@
+package gojsonq
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Document describes an immutable decoded JSON document. A Document is decoded only once
+// and can be queried concurrently by any number of goroutines using Query.
+// Values returned by the queries share data with the document and must not be modified
+type Document struct {
+	option   option               // options of the JSONQ the document is created from
+	queryMap map[string]QueryFunc // query functions, never modified after creation
+	content  interface{}          // decoded json data, never modified after creation
+	indexes  []*index             // secondary indexes, never modified after creation
+}
+
+// Document returns an immutable Document from the decoded content of JSONQ.
+// Query functions registered by Macro are available to the queries of the document
+func (j *JSONQ) Document() (*Document, error) {
+	if err := j.Error(); err != nil {
+		return nil, err
+	}
+	qm := make(map[string]QueryFunc, len(j.queryMap))
+	for op, fn := range j.queryMap {
+		qm[op] = fn
+	}
+	return &Document{
+		option:   j.option,
+		queryMap: qm,
+		content:  j.rootJSONContent,
+		indexes:  j.indexes[:len(j.indexes):len(j.indexes)],
+	}, nil
+}
+
+// Query returns a new empty query over the document
+func (d *Document) Query() Query {
+	return Query{doc: d}
+}
+
+// NewQuery returns a new query over an empty document, it is meant to be compiled and run against other documents.
+// e.g: NewQuery().From("items").WhereEqual("sku", "a").Compile()
+func NewQuery(options ...OptionFunc) Query {
+	doc, err := New(options...).Document()
+	if err != nil {
+		return Query{err: err}
+	}
+	return doc.Query()
+}
+
+// Query describes a query builder with value semantics: every method returns a new Query and leaves
+// the receiver untouched, so a Query can be shared and extended concurrently.
+// Regardless of the call order, the clauses are applied as From, Where/OrWhere, Distinct, SortBy, Select, Offset and Limit
+type Query struct {
+	doc        *Document
+	err        error
+	node       string
+	queries    [][]query
+	distinct   string
+	sortBy     []string
+	attributes []string
+	offset     int
+	limit      int
+}
+
+// From seeks the json content to provided node. e.g: "users.[0]"  or "users.[0].name"
+func (q Query) From(node string) Query {
+	q.node = node
+	return q
+}
+
+// Where builds a where clause. e.g: Where("name", "contains", "doe")
+func (q Query) Where(key, cond string, val interface{}) Query {
+	qq := copyQueries(q.queries)
+	if len(qq) == 0 {
+		qq = append(qq, nil)
+	}
+	qq[len(qq)-1] = append(qq[len(qq)-1], query{key: key, operator: cond, value: val})
+	q.queries = qq
+	return q
+}
+
+// OrWhere builds an OrWhere clause, basically it's a group of AND clauses
+func (q Query) OrWhere(key, cond string, val interface{}) Query {
+	q.queries = append(copyQueries(q.queries), []query{{key: key, operator: cond, value: val}})
+	return q
+}
+
+// WhereEqual is an alias of Where("key", "=", val)
+func (q Query) WhereEqual(key string, val interface{}) Query {
+	return q.Where(key, operatorEq, val)
+}
+
+// WhereNotEqual is an alias of Where("key", "!=", val)
+func (q Query) WhereNotEqual(key string, val interface{}) Query {
+	return q.Where(key, operatorNotEq, val)
+}
+
+// WhereNil is an alias of Where("key", "=", nil)
+func (q Query) WhereNil(key string) Query {
+	return q.Where(key, operatorEq, nil)
+}
+
+// WhereNotNil is an alias of Where("key", "!=", nil)
+func (q Query) WhereNotNil(key string) Query {
+	return q.Where(key, operatorNotEq, nil)
+}
+
+// WhereIn is an alias for where("key", "in", []string{"a", "b"})
+func (q Query) WhereIn(key string, val interface{}) Query {
+	return q.Where(key, operatorIn, val)
+}
+
+// WhereNotIn is an alias for where("key", "notIn", []string{"a", "b"})
+func (q Query) WhereNotIn(key string, val interface{}) Query {
+	return q.Where(key, operatorNotIn, val)
+}
+
+// WhereStartsWith satisfies Where clause which starts with provided value(string)
+func (q Query) WhereStartsWith(key string, val interface{}) Query {
+	return q.Where(key, operatorStartsWith, val)
+}
+
+// WhereEndsWith satisfies Where clause which ends with provided value(string)
+func (q Query) WhereEndsWith(key string, val interface{}) Query {
+	return q.Where(key, operatorEndsWith, val)
+}
+
+// WhereContains satisfies Where clause which contains provided value(string)
+func (q Query) WhereContains(key string, val interface{}) Query {
+	return q.Where(key, operatorContains, val)
+}
+
+// Distinct builds distinct value using provided attribute/column/property
+func (q Query) Distinct(property string) Query {
+	q.distinct = property
+	return q
+}
+
+// SortBy sorts an array of objects, default ascending order, pass "desc" for descending order
+func (q Query) SortBy(order ...string) Query {
+	q.sortBy = append(make([]string, 0, len(order)), order...)
+	return q
+}
+
+// Select use for selection of the properties from query result
+func (q Query) Select(properties ...string) Query {
+	q.attributes = append(append(make([]string, 0, len(q.attributes)+len(properties)), q.attributes...), properties...)
+	return q
+}
+
+// Offset skips the number of records in result
+func (q Query) Offset(offset int) Query {
+	q.offset = offset
+	return q
+}
+
+// Limit limits the number of records in result
+func (q Query) Limit(limit int) Query {
+	q.limit = limit
+	return q
+}
+
+// JSONQ returns a new JSONQ instance with the query applied, which gives access to the
+// whole JSONQ API e.g: q.JSONQ().Sum("price"). The returned instance is not shared
+func (q Query) JSONQ() *JSONQ {
+	j := q.build()
+	qm := make(map[string]QueryFunc, len(j.queryMap))
+	for op, fn := range j.queryMap {
+		qm[op] = fn
+	}
+	j.queryMap = qm
+	j.queries = copyQueries(j.queries)
+	return j
+}
+
+// Explain returns the plan of the query without running it
+func (q Query) Explain() *Plan {
+	if q.doc == nil {
+		return &Plan{Node: q.node}
+	}
+	j := &JSONQ{
+		option:           q.doc.option,
+		queryMap:         q.doc.queryMap,
+		rootJSONContent:  q.doc.content,
+		jsonContent:      q.doc.content,
+		indexes:          q.doc.indexes,
+		queries:          q.queries,
+		distinctProperty: q.distinct,
+		attributes:       q.attributes,
+		offsetRecords:    q.offset,
+		limitRecords:     q.limit,
+	}
+	if q.node != "" {
+		j.From(q.node)
+	}
+	if property, asc, err := sortByOrder(q.sortBy); q.sortBy != nil && err == nil {
+		j.sortPlan = &SortPlan{Property: property, Ascending: asc}
+	}
+	return j.Explain()
+}
+
+// build returns a new JSONQ instance with the query applied. The instance shares the query map
+// and the queries with the document and the query, which are never modified by JSONQ itself
+func (q Query) build() *JSONQ {
+	if q.err != nil {
+		return New().addError(q.err)
+	}
+	if q.doc == nil {
+		return New().addError(errors.New("query is not bound to any document"))
+	}
+	j := &JSONQ{
+		option:          q.doc.option,
+		queryMap:        q.doc.queryMap,
+		rootJSONContent: q.doc.content,
+		jsonContent:     q.doc.content,
+		indexes:         q.doc.indexes,
+	}
+	if q.node != "" {
+		j.From(q.node)
+	}
+	j.queries = q.queries
+	if len(q.queries) > 0 {
+		j.queryIndex = len(q.queries) - 1
+	}
+	if q.distinct != "" {
+		j.Distinct(q.distinct)
+	}
+	if q.sortBy != nil {
+		j.SortBy(q.sortBy...)
+		// queries and distinct are already applied by SortBy
+		j.queries, j.queryIndex, j.distinctProperty = nil, 0, ""
+	}
+	j.Select(q.attributes...)
+	j.Offset(q.offset)
+	j.Limit(q.limit)
+	return j
+}
+
+// Get return the result
+func (q Query) Get() (interface{}, error) {
+	j := q.build()
+	v := j.Get()
+	return v, j.Error()
+}
+
+// GetR return the query results as Result instance
+func (q Query) GetR() (*Result, error) {
+	return q.build().GetR()
+}
+
+// First returns the first element of a list
+func (q Query) First() (interface{}, error) {
+	j := q.build()
+	v := j.First()
+	return v, j.Error()
+}
+
+// FirstR returns the first element of a list as Result instance
+func (q Query) FirstR() (*Result, error) {
+	return q.build().FirstR()
+}
+
+// Find returns the result of a exact matching path
+func (q Query) Find(path string) (interface{}, error) {
+	j := q.build()
+	v := j.Find(path)
+	return v, j.Error()
+}
+
+// FindR returns the result as Result instance from the exact matching path
+func (q Query) FindR(path string) (*Result, error) {
+	return q.build().FindR(path)
+}
+
+// Pluck build an array of values form a property of a list of objects
+func (q Query) Pluck(property string) (interface{}, error) {
+	j := q.build()
+	v := j.Pluck(property)
+	return v, j.Error()
+}
+
+// Count returns the number of total items
+func (q Query) Count() (int, error) {
+	j := q.build()
+	c := j.Count()
+	return c, j.Error()
+}
+
+// Out write the queried data to defined custom type
+func (q Query) Out(v interface{}) error {
+	j := q.build()
+	j.Out(v)
+	return j.Error()
+}
+
+// String satisfies stringer interface
+func (q Query) String() string {
+	return fmt.Sprintf("\nNode: %s\nQueries:%v\n", q.node, q.queries)
+}
+
+// copyQueries returns a deep copy of the queries so that appending never modifies the original
+func copyQueries(queries [][]query) [][]query {
+	qq := make([][]query, len(queries), len(queries)+1)
+	for i, q := range queries {
+		qq[i] = append(make([]query, 0, len(q)+1), q...)
+	}
+	return qq
+}
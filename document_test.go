@@ -0,0 +1,189 @@
+package gojsonq
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestJSONQ_Document(t *testing.T) {
+	doc, err := New().FromString(jsonStr).Document()
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+	name, err := doc.Query().Find("vendor.name")
+	if err != nil || name != "Star Trek" {
+		t.Errorf("failed to query document, got: %v %v", name, err)
+	}
+
+	if _, err := New().FromString(`{"invalid"}`).Document(); err == nil {
+		t.Error("failed to catch decoding error")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	doc, _ := New().FromString(jsonStr).Document()
+	items := doc.Query().From("vendor.items")
+
+	testCases := []struct {
+		tag      string
+		query    Query
+		expected string
+	}{
+		{
+			tag:      "where, sort, select and limit",
+			query:    items.Where("price", ">", 1000).SortBy("price", "desc").Select("name").Limit(2),
+			expected: `[{"name":"MacBook Pro 15 inch retina"},{"name":"MacBook Pro 13 inch retina"}]`,
+		},
+		{
+			tag:      "or where",
+			query:    items.WhereEqual("id", 1).OrWhere("id", "=", 3).OrWhere("name", "=", "HP core i7").Select("id"),
+			expected: `[{"id":1},{"id":3},{"id":6}]`,
+		},
+		{
+			tag:      "select before sort",
+			query:    items.Select("id").WhereIn("id", []int{1, 2, 3}).SortBy("price").Offset(1),
+			expected: `[{"id":1},{"id":2}]`,
+		},
+		{
+			tag:      "distinct",
+			query:    items.Distinct("price").WhereStartsWith("name", "HP").Select("id"),
+			expected: `[{"id":5},{"id":6}]`,
+		},
+		{
+			tag:      "where nil",
+			query:    items.WhereNil("id").Select("name"),
+			expected: `[{"name":"HP core i3 SSD"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		v, err := tc.query.Get()
+		if err != nil {
+			t.Errorf("Tag: %s\nunexpected error: %v", tc.tag, err)
+		}
+		assertJSON(t, v, tc.expected, tc.tag)
+	}
+
+	// the base query must stay untouched
+	if c, _ := items.Count(); c != 7 {
+		t.Errorf("base query modified, expected 7 items got %d", c)
+	}
+}
+
+func TestQuery_value_semantics(t *testing.T) {
+	doc, _ := New().FromString(jsonStr).Document()
+	base := doc.Query().From("vendor.items").WhereEqual("price", 850)
+	a := base.WhereEqual("id", 4)
+	b := base.WhereEqual("id", 5)
+	c := base.OrWhere("id", "=", 1)
+
+	for tag, tc := range map[string]struct {
+		query Query
+		count int
+	}{
+		"base": {base, 3}, "a": {a, 1}, "b": {b, 1}, "c": {c, 4},
+	} {
+		if n, _ := tc.query.Count(); n != tc.count {
+			t.Errorf("query %s expected %d rows got %d", tag, tc.count, n)
+		}
+	}
+}
+
+func TestQuery_terminals(t *testing.T) {
+	doc, _ := New().FromString(jsonStr).Document()
+	items := doc.Query().From("vendor.items")
+
+	if v, err := items.First(); err != nil || fmt.Sprint(v.(map[string]interface{})["id"]) != "1" {
+		t.Errorf("failed to get first, got: %v %v", v, err)
+	}
+	if r, err := items.FirstR(); err != nil || r.Nil() {
+		t.Errorf("failed to get first as result, got: %v %v", r, err)
+	}
+	if r, err := items.GetR(); err != nil || r.Nil() {
+		t.Errorf("failed to get result, got: %v %v", r, err)
+	}
+	if r, err := doc.Query().FindR("vendor.prices.[0]"); err != nil {
+		t.Errorf("failed to find result, got: %v %v", r, err)
+	}
+	if v, err := items.Pluck("id"); err != nil {
+		t.Errorf("failed to pluck, got: %v %v", v, err)
+	}
+	if sum := items.JSONQ().Sum("price"); sum != 7750 {
+		t.Errorf("failed to sum using JSONQ, got: %v", sum)
+	}
+
+	var out []struct {
+		Name string `json:"name"`
+	}
+	if err := items.Where("id", "=", 1).Out(&out); err != nil || len(out) != 1 || out[0].Name != "MacBook Pro 13 inch retina" {
+		t.Errorf("failed to write out, got: %v %v", out, err)
+	}
+
+	if _, err := items.Where("id", "invalid", 1).Get(); err == nil {
+		t.Error("failed to catch invalid operator")
+	}
+	if _, err := (Query{}).Get(); err == nil {
+		t.Error("failed to catch query without document")
+	}
+}
+
+func TestQuery_JSONQ_is_not_shared(t *testing.T) {
+	doc, _ := New().FromString(jsonStr).Document()
+	q := doc.Query().From("vendor.items").WhereEqual("price", 850)
+	q.JSONQ().Macro("custom", eq).Where("id", "custom", 4)
+	if _, ok := doc.queryMap["custom"]; ok {
+		t.Error("macro of JSONQ must not modify the document")
+	}
+	if n, _ := q.Count(); n != 3 {
+		t.Errorf("query modified by JSONQ, expected 3 rows got %d", n)
+	}
+}
+
+// TestQuery_concurrent runs many queries on the same document in parallel,
+// run with -race to detect data races
+func TestQuery_concurrent(t *testing.T) {
+	jq := New().FromString(jsonStr)
+	jq.Macro("even", func(x, y interface{}) (bool, error) {
+		f, _ := x.(float64)
+		return int(f)%2 == 0, nil
+	})
+	doc, err := jq.Document()
+	if err != nil {
+		t.Fatalf("failed to create document: %v", err)
+	}
+	base := doc.Query().From("vendor.items")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			q := base.Where("price", ">", float64(i*10))
+			if i%2 == 0 {
+				q = q.SortBy("price", "desc")
+			} else {
+				q = q.SortBy("name")
+			}
+			if _, err := q.Where("id", "even", nil).Select("id", "name").Get(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if _, err := base.Count(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// sorting must never reorder the document
+	first, _ := base.First()
+	assertJSON(t, first, `{"id":1,"name":"MacBook Pro 13 inch retina","price":1350}`, "document must stay untouched")
+}
+
+func TestJSONQ_Copy_does_not_share_query_map(t *testing.T) {
+	jq := New()
+	cp := jq.Copy().Macro("custom", eq)
+	if _, ok := jq.queryMap["custom"]; ok || cp.Error() != nil {
+		t.Error("copy must not share the query map")
+	}
+}
@@ -0,0 +1,172 @@
+package gojsonq
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Encoder provide contract to encode the result using custom encoder, see WithEncoder
+type Encoder interface {
+	Encode(w io.Writer, v interface{}) error
+}
+
+// ColumnEncoder is an Encoder of a tabular format, columns are the names of the selected
+// properties in the order of Select, empty when nothing is selected
+type ColumnEncoder interface {
+	Encoder
+	EncodeColumns(w io.Writer, v interface{}, columns []string) error
+}
+
+// DefaultEncoder use json.Encoder to encode the result as a single line JSON
+type DefaultEncoder struct{}
+
+// Encode encodes using json.Encoder
+func (e *DefaultEncoder) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// PrettyEncoder encodes the result as indented JSON, the keys are sorted and the HTML characters are not escaped
+type PrettyEncoder struct {
+	Indent string // indentation of each level, default two spaces
+}
+
+// Encode encodes using json.Encoder with indentation
+func (e *PrettyEncoder) Encode(w io.Writer, v interface{}) error {
+	indent := e.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", indent)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// NDJSONEncoder encodes each row of a list as a single line JSON, any other result is written on a single line
+type NDJSONEncoder struct{}
+
+// Encode encodes a row per line
+func (e *NDJSONEncoder) Encode(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	aa, ok := v.([]interface{})
+	if !ok {
+		return enc.Encode(v)
+	}
+	for _, a := range aa {
+		if err := enc.Encode(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CSVEncoder encodes a list of objects as CSV with a header, nested values are flattened into
+// columns named by their path, e.g: vendor.name, tags.[0]. Rows which are not objects are written
+// in a single column named value
+type CSVEncoder struct {
+	Comma     rune   // field delimiter, default comma
+	Separator string // separator of the flattened column names, default DOT (.)
+}
+
+// Encode encodes the result with the columns sorted by name
+func (e *CSVEncoder) Encode(w io.Writer, v interface{}) error {
+	return e.EncodeColumns(w, v, nil)
+}
+
+// EncodeColumns encodes the result in the order of columns, the flattened columns of a nested
+// value follow their column in order of name and the columns missing in every row are skipped
+func (e *CSVEncoder) EncodeColumns(w io.Writer, v interface{}, columns []string) error {
+	sep := e.Separator
+	if sep == "" {
+		sep = defaultSeparator
+	}
+
+	var rows []map[string]interface{}
+	aa, ok := v.([]interface{})
+	if !ok {
+		aa = []interface{}{v}
+	}
+	var keys []string
+	seen := map[string]bool{}
+	for _, a := range aa {
+		m, ok := objectOf(a)
+		if !ok {
+			m = map[string]interface{}{"value": a}
+		}
+		row := flattenKeys(m, sep)
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Strings(keys)
+	header := csvHeader(keys, columns, sep)
+
+	cw := csv.NewWriter(w)
+	if e.Comma != 0 {
+		cw.Comma = e.Comma
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for i, h := range header {
+			record[i] = csvValue(row[h])
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvHeader orders the flattened keys by columns, all the keys are used when columns is empty
+func csvHeader(keys, columns []string, sep string) []string {
+	if len(columns) == 0 {
+		return keys
+	}
+	header := make([]string, 0, len(keys))
+	for _, c := range columns {
+		for _, k := range keys {
+			if k == c || strings.HasPrefix(k, c+sep) {
+				header = append(header, k)
+			}
+		}
+	}
+	return header
+}
+
+// csvValue formats a flattened value, null is an empty field
+func csvValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return formatNumber(x)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// formatNumber formats f like encoding/json does
+func formatNumber(f float64) string {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return string(data)
+}
@@ -0,0 +1,104 @@
+package gojsonq
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncoders(t *testing.T) {
+	rows := `[{"id":1,"name":"<a&b>","vendor":{"name":"x","tags":["p","q"]}},{"id":2.5,"name":null,"extra":true}]`
+	testCases := []struct {
+		tag      string
+		encoder  Encoder
+		expected string
+	}{
+		{
+			tag:      "default",
+			encoder:  &DefaultEncoder{},
+			expected: `[{"id":1,"name":"\u003ca\u0026b\u003e","vendor":{"name":"x","tags":["p","q"]}},{"extra":true,"id":2.5,"name":null}]` + "\n",
+		},
+		{
+			tag:     "pretty",
+			encoder: &PrettyEncoder{Indent: "\t"},
+			expected: `[
+	{
+		"id": 1,
+		"name": "<a&b>",
+		"vendor": {
+			"name": "x",
+			"tags": [
+				"p",
+				"q"
+			]
+		}
+	},
+	{
+		"extra": true,
+		"id": 2.5,
+		"name": null
+	}
+]
+`,
+		},
+		{
+			tag:     "ndjson",
+			encoder: &NDJSONEncoder{},
+			expected: `{"id":1,"name":"<a&b>","vendor":{"name":"x","tags":["p","q"]}}
+{"extra":true,"id":2.5,"name":null}
+`,
+		},
+		{
+			tag:     "csv",
+			encoder: &CSVEncoder{},
+			expected: `extra,id,name,vendor.name,vendor.tags.[0],vendor.tags.[1]
+,1,<a&b>,x,p,q
+true,2.5,,,,
+`,
+		},
+		{
+			tag:     "csv with options",
+			encoder: &CSVEncoder{Comma: ';', Separator: "_"},
+			expected: `extra;id;name;vendor_name;vendor_tags_[0];vendor_tags_[1]
+;1;<a&b>;x;p;q
+true;2.5;;;;
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		var b bytes.Buffer
+		jq := New(WithEncoder(tc.encoder)).FromString(rows)
+		jq.Writer(&b)
+		if jq.Error() != nil {
+			t.Errorf("%s: unexpected error: %v", tc.tag, jq.Error())
+		}
+		assertInterface(t, tc.expected, b.String(), tc.tag)
+	}
+}
+
+func TestCSVEncoder_select_order(t *testing.T) {
+	var b bytes.Buffer
+	jq := New(WithEncoder(&CSVEncoder{})).FromString(jsonStr).
+		From("vendor.items").
+		Select("price", "name as title", "id").
+		WhereIn("id", []int{1, 3})
+	jq.Writer(&b)
+	expected := `price,title,id
+1350,MacBook Pro 13 inch retina,1
+1200,Sony VAIO,3
+`
+	assertInterface(t, expected, b.String(), "csv in the order of Select")
+}
+
+func TestCSVEncoder_non_object_rows(t *testing.T) {
+	var b bytes.Buffer
+	New(WithEncoder(&CSVEncoder{})).FromString(jsonStr).From("vendor.prices").Writer(&b)
+	expected := "value\n2400\n2100\n1200\n400.87\n89.9\n150.1\n"
+	assertInterface(t, expected, b.String(), "csv of a list of values")
+}
+
+func TestNDJSONEncoder_single_value(t *testing.T) {
+	var b bytes.Buffer
+	New(WithEncoder(&NDJSONEncoder{})).FromString(jsonStr).From("vendor.name").Writer(&b)
+	assertInterface(t, "\"Star Trek\"\n", b.String(), "ndjson of a single value")
+}
@@ -0,0 +1,151 @@
+package gojsonq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Named error values reported by the queries, use errors.Is to check them
+var (
+	ErrInvalidNode     = errors.New("invalid node name")
+	ErrInvalidIndex    = errors.New("invalid index")
+	ErrIndexOutOfRange = errors.New("index out of range")
+	ErrEmptyList       = errors.New("list is empty")
+	ErrInvalidOperator = errors.New("invalid operator")
+	ErrOperatorExists  = errors.New("operator is already registered")
+	ErrInputTooLarge   = errors.New("input exceeds the maximum size")
+	ErrTooDeep         = errors.New("input exceeds the maximum depth")
+	ErrTooManyRows     = errors.New("result exceeds the maximum rows")
+	ErrOverflow        = errors.New("value overflows the type")
+	ErrPrecision       = errors.New("value loses precision")
+)
+
+// PathError describes a failure to traverse a path, e.g: a missing key or an index out of range
+type PathError struct {
+	Path    string // path being traversed, empty if unknown
+	Segment string // segment of the path the traversal failed at
+	Err     error  // one of ErrInvalidNode, ErrInvalidIndex, ErrIndexOutOfRange
+}
+
+func (e *PathError) Error() string {
+	if e.Err == ErrInvalidNode {
+		return fmt.Sprintf("invalid node name %s", e.Segment)
+	}
+	return fmt.Sprintf("%v %s", e.Err, e.Segment)
+}
+
+// Unwrap returns the underlying error
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// OperatorError describes an invalid operator of a where clause or a Macro
+type OperatorError struct {
+	Operator string
+	Err      error // one of ErrInvalidOperator, ErrOperatorExists
+}
+
+func (e *OperatorError) Error() string {
+	if e.Err == ErrOperatorExists {
+		return fmt.Sprintf("%s is already registered in query map", e.Operator)
+	}
+	return fmt.Sprintf("%v %s", e.Err, e.Operator)
+}
+
+// Unwrap returns the underlying error
+func (e *OperatorError) Unwrap() error {
+	return e.Err
+}
+
+// TypeError describes a value of unexpected type given to a query function
+type TypeError struct {
+	Value    interface{}
+	Expected string // expected type, e.g: numeric, string, integer
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("%v must be %s", e.Value, e.Expected)
+}
+
+// CoercionError describes a value which can not be coerced to the type of a Result accessor
+type CoercionError struct {
+	Value interface{}
+	Type  string // target type, e.g: int8, bool, time.Time
+	Err   error  // ErrOverflow, ErrPrecision or the parsing error
+}
+
+func (e *CoercionError) Error() string {
+	return fmt.Sprintf("cannot coerce %v to %s: %v", e.Value, e.Type, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *CoercionError) Unwrap() error {
+	return e.Err
+}
+
+// PredicateError describes the failure of a where clause on a row of the list
+type PredicateError struct {
+	Row      int    // position of the row in the list
+	Key      string // key of the where clause
+	Operator string // operator of the where clause
+	Err      error
+}
+
+func (e *PredicateError) Error() string {
+	return fmt.Sprintf("where %s %s failed at row %d: %v", e.Key, e.Operator, e.Row, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *PredicateError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError describes a failure to decode the json content
+type DecodeError struct {
+	Offset int64 // number of bytes read before the failure, 0 if unknown
+	Line   int   // line of the failure starting from 1, 0 if unknown
+	Column int   // column of the failure starting from 1, 0 if unknown
+	Err    error // error returned by the decoder
+}
+
+func (e *DecodeError) Error() string {
+	if e.Line == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v (line %d, column %d)", e.Err, e.Line, e.Column)
+}
+
+// Unwrap returns the underlying error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// newDecodeError wraps the error of the decoder, the position is known only for encoding/json errors
+func newDecodeError(raw []byte, err error) *DecodeError {
+	de := &DecodeError{Err: err}
+	var se *json.SyntaxError
+	var te *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &se):
+		de.Offset = se.Offset
+	case errors.As(err, &te):
+		de.Offset = te.Offset
+	}
+	// the offset is reported after reading the offending byte
+	if de.Offset > 0 && de.Offset <= int64(len(raw)) {
+		before := raw[:de.Offset-1]
+		de.Line = bytes.Count(before, []byte("\n")) + 1
+		de.Column = len(before) - bytes.LastIndexByte(before, '\n')
+	}
+	return de
+}
+
+// withPath sets the path of a PathError
+func withPath(err error, path string) error {
+	if pe, ok := err.(*PathError); ok && pe.Path == "" {
+		pe.Path = path
+	}
+	return err
+}
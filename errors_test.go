@@ -0,0 +1,100 @@
+package gojsonq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrors_sentinels(t *testing.T) {
+	testCases := []struct {
+		tag    string
+		jq     *JSONQ
+		target error
+	}{
+		{tag: "invalid node", jq: New().FromString(jsonStr).From("vendor.invalid"), target: ErrInvalidNode},
+		{tag: "invalid index", jq: New().FromString(jsonStr).From("vendor.items.[x]"), target: ErrInvalidIndex},
+		{tag: "index out of range", jq: New().FromString(jsonStr).From("vendor.items.[20]"), target: ErrIndexOutOfRange},
+		{tag: "invalid operator", jq: New().FromString(jsonStr).From("vendor.items").Where("id", "invalid", 1), target: ErrInvalidOperator},
+		{tag: "operator exists", jq: New().Macro("=", eq), target: ErrOperatorExists},
+		{tag: "missing key", jq: New().FromString(jsonStr).From("vendor.items").WhereEqual("key", 1), target: ErrInvalidNode},
+	}
+
+	for _, tc := range testCases {
+		tc.jq.Get()
+		if err := tc.jq.Error(); !errors.Is(err, tc.target) {
+			t.Errorf("tag: %s, expected %v got: %v", tc.tag, tc.target, err)
+		}
+	}
+
+	jq := New().FromString(jsonStr).From("vendor.items")
+	jq.Nth(20)
+	if !errors.Is(jq.Error(), ErrIndexOutOfRange) {
+		t.Errorf("expected Nth index out of range, got: %v", jq.Error())
+	}
+	jq = New().FromString(`[]`)
+	jq.Nth(1)
+	if !errors.Is(jq.Error(), ErrEmptyList) {
+		t.Errorf("expected Nth empty list, got: %v", jq.Error())
+	}
+}
+
+func TestErrors_PathError(t *testing.T) {
+	err := New().FromString(jsonStr).From("vendor.items.[0].invalid").Error()
+	var pe *PathError
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected PathError, got: %v", err)
+	}
+	if pe.Path != "vendor.items.[0].invalid" || pe.Segment != "invalid" {
+		t.Errorf("unexpected path error: %+v", pe)
+	}
+	// the message is unchanged
+	if err.Error() != "gojsonq: invalid node name invalid" {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestErrors_PredicateError(t *testing.T) {
+	jq := New().FromString(jsonStr).From("vendor.items").Where("name", ">", 1)
+	jq.Get()
+	var pde *PredicateError
+	if !errors.As(jq.Error(), &pde) {
+		t.Fatalf("expected PredicateError, got: %v", jq.Error())
+	}
+	if pde.Row != 0 || pde.Key != "name" || pde.Operator != ">" {
+		t.Errorf("unexpected predicate error: %+v", pde)
+	}
+	var te *TypeError
+	if !errors.As(jq.Error(), &te) || te.Expected != "numeric" || te.Value != "MacBook Pro 13 inch retina" {
+		t.Errorf("expected TypeError, got: %v", jq.Error())
+	}
+
+	// the row is the position in the list, also when an index is used
+	jq = New().FromString(`[{"p":1},{"p":2},{"p":"x"}]`).CreateIndex("", "p")
+	jq.Where("p", ">", 1).Get()
+	if !errors.As(jq.Error(), &pde) || pde.Row != 2 || !errors.As(jq.Error(), &te) || te.Value != "x" {
+		t.Errorf("unexpected predicate error: %v", jq.Error())
+	}
+}
+
+func TestErrors_DecodeError(t *testing.T) {
+	err := New().FromString("{\n  \"name\": \"gojsonq\",\n  \"tags\": [1, 2,]\n}").Error()
+	var de *DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got: %v", err)
+	}
+	if de.Line != 3 || de.Column != 17 || de.Offset != 40 {
+		t.Errorf("unexpected position: %+v", de)
+	}
+
+	err = New(WithDecoder(&errDecoder{})).FromString(`{}`).Error()
+	if !errors.As(err, &de) || de.Line != 0 {
+		t.Errorf("expected DecodeError without position, got: %v", err)
+	}
+}
+
+// errDecoder always fails without reporting the position of the failure
+type errDecoder struct{}
+
+func (d *errDecoder) Decode(data []byte, v interface{}) error {
+	return errors.New("broken decoder")
+}
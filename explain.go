@@ -0,0 +1,131 @@
+package gojsonq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Plan describes how the query is going to be processed
+type Plan struct {
+	Node     string            // node the query starts from
+	Stages   int               // number of pending joins, set operations and unwinds applied before the where clauses
+	Groups   [][]PredicatePlan // where clauses, one group of AND clauses per OrWhere
+	Index    *IndexPlan        // index used to find the candidate rows, nil for a full scan
+	Distinct string            // distinct property
+	Sort     *SortPlan         // last sort applied to the list, nil if not sorted
+	Select   []string          // names of the selected columns
+	Offset   int
+	Limit    int
+}
+
+// PredicatePlan describes a where clause
+type PredicatePlan struct {
+	Key      string
+	Operator string
+	Value    interface{}
+}
+
+// IndexPlan describes the index used by a query
+type IndexPlan struct {
+	Path     string // path of the indexed list
+	KeyPath  string // indexed key
+	Kind     string // hash or sorted
+	Operator string // operator of the predicate served by the index
+}
+
+// SortPlan describes the sort applied to the list
+type SortPlan struct {
+	Property  string // empty when sorting a list of values
+	Ascending bool
+}
+
+// Explain returns the plan of the query without running it
+func (j *JSONQ) Explain() *Plan {
+	plan := &Plan{
+		Node:     j.node,
+		Stages:   len(j.stages),
+		Distinct: j.distinctProperty,
+		Sort:     j.sortPlan,
+		Offset:   j.offsetRecords,
+		Limit:    j.limitRecords,
+	}
+
+	groups := j.predicates
+	if groups == nil {
+		for _, qList := range j.queries {
+			group := make([]predicate, 0, len(qList))
+			for _, q := range qList {
+				group = append(group, predicate{query: q})
+			}
+			groups = append(groups, group)
+		}
+	}
+	for _, group := range groups {
+		pp := make([]PredicatePlan, 0, len(group))
+		for _, p := range group {
+			pp = append(pp, PredicatePlan{Key: p.key, Operator: p.operator, Value: p.value})
+		}
+		plan.Groups = append(plan.Groups, pp)
+	}
+
+	for _, c := range append(j.columns(j.attributes), j.selectColumns...) {
+		plan.Select = append(plan.Select, c.alias)
+	}
+
+	if aa, ok := j.jsonContent.([]interface{}); ok && len(j.stages) == 0 {
+		if idx, p := j.findIndex(aa, groups); idx != nil {
+			plan.Index = &IndexPlan{
+				Path:     idx.path,
+				KeyPath:  idx.keyPath,
+				Kind:     idx.kind(p.operator),
+				Operator: p.operator,
+			}
+		}
+	}
+	return plan
+}
+
+// String satisfies stringer interface
+func (p *Plan) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Node: %s\n", p.Node)
+	if p.Stages > 0 {
+		fmt.Fprintf(&sb, "Stages: %d\n", p.Stages)
+	}
+	for i, group := range p.Groups {
+		cc := make([]string, 0, len(group))
+		for _, c := range group {
+			cc = append(cc, fmt.Sprintf("%s %s %v", c.Key, c.Operator, c.Value))
+		}
+		if i == 0 {
+			fmt.Fprintf(&sb, "Where: %s\n", strings.Join(cc, " AND "))
+		} else {
+			fmt.Fprintf(&sb, "OrWhere: %s\n", strings.Join(cc, " AND "))
+		}
+	}
+	if p.Index != nil {
+		fmt.Fprintf(&sb, "Index: %s on %s (%s, %s)\n", p.Index.KeyPath, p.Index.Path, p.Index.Kind, p.Index.Operator)
+	} else {
+		sb.WriteString("Index: none (full scan)\n")
+	}
+	if p.Distinct != "" {
+		fmt.Fprintf(&sb, "Distinct: %s\n", p.Distinct)
+	}
+	if p.Sort != nil {
+		order := "asc"
+		if !p.Sort.Ascending {
+			order = "desc"
+		}
+		fmt.Fprintf(&sb, "Sort: %s %s\n", p.Sort.Property, order)
+	}
+	if len(p.Select) > 0 {
+		fmt.Fprintf(&sb, "Select: %s\n", strings.Join(p.Select, ", "))
+	}
+	if p.Offset != 0 {
+		fmt.Fprintf(&sb, "Offset: %d\n", p.Offset)
+	}
+	if p.Limit != 0 {
+		fmt.Fprintf(&sb, "Limit: %d\n", p.Limit)
+	}
+	return sb.String()
+}
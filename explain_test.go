@@ -0,0 +1,83 @@
+package gojsonq
+
+import "testing"
+
+func TestJSONQ_Explain(t *testing.T) {
+	jq := New().FromString(jsonStr).CreateIndex("vendor.items", "price")
+
+	plan := jq.From("vendor.items").Where("price", ">", 900).Explain()
+	if plan.Node != "vendor.items" || plan.Index == nil {
+		t.Fatalf("expected index plan, got: %+v", plan)
+	}
+	expected := IndexPlan{Path: "vendor.items", KeyPath: "price", Kind: "sorted", Operator: ">"}
+	if *plan.Index != expected {
+		t.Errorf("expected: %+v got: %+v", expected, *plan.Index)
+	}
+
+	jq.Reset()
+	if plan := jq.From("vendor.items").WhereEqual("name", "HP core i7").Explain(); plan.Index != nil {
+		t.Errorf("expected full scan, got: %+v", plan.Index)
+	}
+
+	// explain does not run the query
+	jq.Reset()
+	jq.From("vendor.items").WhereEqual("price", 850).Explain()
+	assertJSON(t, jq.Pluck("id"), `[4,5,null]`, "query after explain")
+}
+
+func TestJSONQ_Explain_plan(t *testing.T) {
+	jq := New().FromString(jsonStr).
+		From("vendor.items").
+		Where("price", ">", 900).
+		Where("name", "contains", "Pro").
+		OrWhere("id", "=", 6).
+		Distinct("price").
+		Select("id", "price * 2 as double").
+		Offset(1).
+		Limit(2)
+	plan := jq.Explain()
+
+	expectedGroups := [][]PredicatePlan{
+		{{Key: "price", Operator: ">", Value: 900}, {Key: "name", Operator: "contains", Value: "Pro"}},
+		{{Key: "id", Operator: "=", Value: 6}},
+	}
+	assertInterface(t, plan.Groups, expectedGroups, "plan groups")
+	assertInterface(t, plan.Select, []string{"id", "double"}, "plan select")
+	if plan.Distinct != "price" || plan.Offset != 1 || plan.Limit != 2 || plan.Sort != nil || plan.Index != nil {
+		t.Errorf("unexpected plan: %+v", plan)
+	}
+
+	jq.SortBy("price", "desc")
+	if plan := jq.Explain(); plan.Sort == nil || *plan.Sort != (SortPlan{Property: "price"}) {
+		t.Errorf("expected descending sort by price, got: %+v", plan.Sort)
+	}
+
+	expected := "Node: vendor.items\n" +
+		"Where: price > 900 AND name contains Pro\n" +
+		"OrWhere: id = 6\n" +
+		"Index: none (full scan)\n" +
+		"Distinct: price\n" +
+		"Sort: price desc\n" +
+		"Select: id, double\n" +
+		"Offset: 1\n" +
+		"Limit: 2\n"
+	if s := jq.Explain().String(); s != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, s)
+	}
+}
+
+func TestQuery_Explain(t *testing.T) {
+	doc, _ := New().FromString(jsonStr).CreateIndex("vendor.items", "id").Document()
+	plan := doc.Query().From("vendor.items").WhereIn("id", []int{1, 2}).SortBy("price").Select("name").Explain()
+	if plan.Index == nil || plan.Index.Kind != "hash" {
+		t.Errorf("expected hash index, got: %+v", plan.Index)
+	}
+	if plan.Sort == nil || *plan.Sort != (SortPlan{Property: "price", Ascending: true}) {
+		t.Errorf("expected ascending sort by price, got: %+v", plan.Sort)
+	}
+	assertInterface(t, plan.Select, []string{"name"}, "query plan select")
+
+	if plan := (Query{node: "items"}).Explain(); plan.Node != "items" {
+		t.Errorf("expected node of unbound query, got: %+v", plan)
+	}
+}
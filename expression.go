@@ -0,0 +1,688 @@
+package gojsonq
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// expr describes an evaluable node of a computed column expression. e.g: price * qty
+type expr interface {
+	eval(row interface{}) (interface{}, error)
+}
+
+// literalExpr is a constant value
+type literalExpr struct {
+	value interface{}
+}
+
+func (e *literalExpr) eval(row interface{}) (interface{}, error) {
+	return e.value, nil
+}
+
+// pathExpr reads the value of a path from the row, a missing path is reported as a PathError
+// handled by the missing key policy
+type pathExpr struct {
+	path, separator string
+}
+
+func (e *pathExpr) eval(row interface{}) (interface{}, error) {
+	v, err := getNestedValue(row, e.path, e.separator)
+	if err != nil {
+		return nil, withPath(err, e.path)
+	}
+	return v, nil
+}
+
+// unaryExpr negates a number or a boolean
+type unaryExpr struct {
+	operator string
+	x        expr
+}
+
+func (e *unaryExpr) eval(row interface{}) (interface{}, error) {
+	x, err := e.x.eval(row)
+	if err != nil || x == nil {
+		return nil, err
+	}
+	if e.operator == "!" {
+		b, ok := x.(bool)
+		if !ok {
+			return nil, &TypeError{Value: x, Expected: "boolean"}
+		}
+		return !b, nil
+	}
+	f, ok := toFloat64(x)
+	if !ok {
+		return nil, &TypeError{Value: x, Expected: "numeric"}
+	}
+	return -f, nil
+}
+
+// binaryExpr applies an arithmetic, comparison or logical operator to two values
+type binaryExpr struct {
+	operator string
+	x, y     expr
+}
+
+func (e *binaryExpr) eval(row interface{}) (interface{}, error) {
+	x, err := e.x.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	switch e.operator {
+	case "&&", "||":
+		xb, ok := x.(bool)
+		if !ok {
+			return nil, &TypeError{Value: x, Expected: "boolean"}
+		}
+		if (e.operator == "&&" && !xb) || (e.operator == "||" && xb) {
+			return xb, nil
+		}
+		y, err := e.y.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		yb, ok := y.(bool)
+		if !ok {
+			return nil, &TypeError{Value: y, Expected: "boolean"}
+		}
+		return yb, nil
+	}
+
+	y, err := e.y.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	switch e.operator {
+	case "==":
+		return isEqual(x, y), nil
+	case "!=":
+		return !isEqual(x, y), nil
+	}
+	if x == nil || y == nil {
+		return nil, nil
+	}
+
+	if xs, ok := x.(string); ok {
+		ys, ok := y.(string)
+		if !ok {
+			return nil, e.operandError(x, y, &TypeError{Value: y, Expected: "string"})
+		}
+		switch e.operator {
+		case "+":
+			return xs + ys, nil
+		case "<":
+			return xs < ys, nil
+		case "<=":
+			return xs <= ys, nil
+		case ">":
+			return xs > ys, nil
+		case ">=":
+			return xs >= ys, nil
+		}
+		return nil, e.operandError(x, y, &TypeError{Value: x, Expected: "numeric"})
+	}
+
+	xf, okX := toFloat64(x)
+	yf, okY := toFloat64(y)
+	if !okX {
+		return nil, e.operandError(x, y, &TypeError{Value: x, Expected: "numeric"})
+	}
+	if !okY {
+		return nil, e.operandError(x, y, &TypeError{Value: y, Expected: "numeric"})
+	}
+	switch e.operator {
+	case "+":
+		return xf + yf, nil
+	case "-":
+		return xf - yf, nil
+	case "*":
+		return xf * yf, nil
+	case "/":
+		if yf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return xf / yf, nil
+	case "%":
+		if yf == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(xf, yf), nil
+	case "<":
+		return xf < yf, nil
+	case "<=":
+		return xf <= yf, nil
+	case ">":
+		return xf > yf, nil
+	default: // >=
+		return xf >= yf, nil
+	}
+}
+
+// operandError describes operands the operator can not be applied to
+func (e *binaryExpr) operandError(x, y interface{}, err *TypeError) error {
+	return fmt.Errorf("invalid operands %v %s %v: %w", x, e.operator, y, err)
+}
+
+// ifExpr evaluates then or otherwise depending on the condition, the other branch is never evaluated
+type ifExpr struct {
+	cond, then, otherwise expr
+}
+
+func (e *ifExpr) eval(row interface{}) (interface{}, error) {
+	c, err := e.cond.eval(row)
+	if err != nil {
+		return nil, err
+	}
+	if b, _ := c.(bool); b {
+		return e.then.eval(row)
+	}
+	return e.otherwise.eval(row)
+}
+
+// coalesceExpr returns the first non nil value, a missing path counts as nil
+type coalesceExpr struct {
+	args []expr
+}
+
+func (e *coalesceExpr) eval(row interface{}) (interface{}, error) {
+	for _, a := range e.args {
+		v, err := a.eval(row)
+		if err != nil && !isMissingKey(err) {
+			return nil, err
+		}
+		if v != nil {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+// callExpr calls a function of the built-in library
+type callExpr struct {
+	name string
+	fn   exprFunc
+	args []expr
+}
+
+func (e *callExpr) eval(row interface{}) (interface{}, error) {
+	args := make([]interface{}, len(e.args))
+	for i, a := range e.args {
+		v, err := a.eval(row)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil && e.fn.strict {
+			return nil, nil
+		}
+		args[i] = v
+	}
+	v, err := e.fn.call(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.name, err)
+	}
+	return v, nil
+}
+
+// exprFunc describes a function of the built-in library
+type exprFunc struct {
+	minArgs, maxArgs int  // maxArgs -1 means variadic
+	strict           bool // a nil argument makes the result nil
+	call             func(args []interface{}) (interface{}, error)
+}
+
+// exprFuncs contains the built-in functions available in computed columns
+var exprFuncs = map[string]exprFunc{
+	// string
+	"upper": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		return strings.ToUpper(s), err
+	}},
+	"lower": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		return strings.ToLower(s), err
+	}},
+	"trim": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		return strings.TrimSpace(s), err
+	}},
+	"length": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		l, err := length(args[0])
+		return float64(l), err
+	}},
+	"substr": {2, 3, true, func(args []interface{}) (interface{}, error) {
+		s, err := stringArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		rs := []rune(s)
+		start, err := numberArg(args[1])
+		if err != nil {
+			return nil, err
+		}
+		from := int(math.Min(math.Max(start, 0), float64(len(rs))))
+		to := len(rs)
+		if len(args) > 2 {
+			n, err := numberArg(args[2])
+			if err != nil {
+				return nil, err
+			}
+			to = int(math.Min(float64(from)+math.Max(n, 0), float64(len(rs))))
+		}
+		return string(rs[from:to]), nil
+	}},
+	"replace": {3, 3, true, func(args []interface{}) (interface{}, error) {
+		ss := make([]string, 3)
+		for i, a := range args {
+			s, err := stringArg(a)
+			if err != nil {
+				return nil, err
+			}
+			ss[i] = s
+		}
+		return strings.Replace(ss[0], ss[1], ss[2], -1), nil
+	}},
+	"contains": {2, 2, true, func(args []interface{}) (interface{}, error) {
+		return strStrictContains(args[0], args[1])
+	}},
+	"concat": {1, -1, false, func(args []interface{}) (interface{}, error) {
+		var sb strings.Builder
+		for _, a := range args {
+			if a != nil {
+				sb.WriteString(toString(a))
+			}
+		}
+		return sb.String(), nil
+	}},
+
+	// math
+	"abs": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		f, err := numberArg(args[0])
+		return math.Abs(f), err
+	}},
+	"floor": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		f, err := numberArg(args[0])
+		return math.Floor(f), err
+	}},
+	"ceil": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		f, err := numberArg(args[0])
+		return math.Ceil(f), err
+	}},
+	"sqrt": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		f, err := numberArg(args[0])
+		return math.Sqrt(f), err
+	}},
+	"round": {1, 2, true, func(args []interface{}) (interface{}, error) {
+		f, err := numberArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		var places float64
+		if len(args) > 1 {
+			if places, err = numberArg(args[1]); err != nil {
+				return nil, err
+			}
+		}
+		p := math.Pow(10, math.Trunc(places))
+		return math.Round(f*p) / p, nil
+	}},
+	"pow": {2, 2, true, func(args []interface{}) (interface{}, error) {
+		x, err := numberArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		y, err := numberArg(args[1])
+		return math.Pow(x, y), err
+	}},
+	"min": {1, -1, false, func(args []interface{}) (interface{}, error) {
+		return extremum(args, func(x, y float64) bool { return x < y })
+	}},
+	"max": {1, -1, false, func(args []interface{}) (interface{}, error) {
+		return extremum(args, func(x, y float64) bool { return x > y })
+	}},
+
+	// date, times are RFC 3339 strings
+	"now": {0, 0, false, func(args []interface{}) (interface{}, error) {
+		return time.Now().UTC().Format(time.RFC3339), nil
+	}},
+	"year": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		t, err := timeArg(args[0])
+		return float64(t.Year()), err
+	}},
+	"month": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		t, err := timeArg(args[0])
+		return float64(t.Month()), err
+	}},
+	"day": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		t, err := timeArg(args[0])
+		return float64(t.Day()), err
+	}},
+	"unix": {1, 1, true, func(args []interface{}) (interface{}, error) {
+		t, err := timeArg(args[0])
+		return float64(t.Unix()), err
+	}},
+	"date": {2, 2, true, func(args []interface{}) (interface{}, error) {
+		t, err := timeArg(args[0])
+		if err != nil {
+			return nil, err
+		}
+		layout, err := stringArg(args[1])
+		return t.Format(layout), err
+	}},
+}
+
+// numberArg converts a function argument to float64
+func numberArg(v interface{}) (float64, error) {
+	f, ok := toFloat64(v)
+	if !ok {
+		return 0, &TypeError{Value: v, Expected: "numeric"}
+	}
+	return f, nil
+}
+
+// stringArg converts a function argument to string
+func stringArg(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", &TypeError{Value: v, Expected: "string"}
+	}
+	return s, nil
+}
+
+// timeArg parses a RFC 3339 function argument
+func timeArg(v interface{}) (time.Time, error) {
+	s, err := stringArg(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// extremum returns the number which wins the comparison against all the others, nil values are ignored
+func extremum(args []interface{}, wins func(x, y float64) bool) (interface{}, error) {
+	var result interface{}
+	for _, a := range args {
+		if a == nil {
+			continue
+		}
+		f, err := numberArg(a)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil || wins(f, result.(float64)) {
+			result = f
+		}
+	}
+	return result, nil
+}
+
+// token kinds of the expression lexer
+const (
+	tokenEOF = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token describes a lexical token of an expression
+type token struct {
+	kind  int
+	text  string
+	value interface{}
+}
+
+// lexExpression splits the expression in tokens. Identifiers may contain the separator,
+// array indexes and dashes so that any node name accepted by From is a valid identifier
+func lexExpression(in, separator string) ([]token, error) {
+	var tokens []token
+	rs := []rune(in)
+	for i := 0; i < len(rs); {
+		c := rs[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(rs) && (unicode.IsDigit(rs[i]) || rs[i] == '.') {
+				i++
+			}
+			f, err := strconv.ParseFloat(string(rs[start:i]), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %s", string(rs[start:i]))
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: f})
+		case c == '\'' || c == '"':
+			var sb strings.Builder
+			i++
+			for ; i < len(rs) && rs[i] != c; i++ {
+				if rs[i] == '\\' && i+1 < len(rs) {
+					i++
+				}
+				sb.WriteRune(rs[i])
+			}
+			if i == len(rs) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenString, value: sb.String()})
+		case isIdentRune(c) || c == '[':
+			start := i
+			for i < len(rs) {
+				if strings.HasPrefix(string(rs[i:]), separator) {
+					i += len([]rune(separator))
+					continue
+				}
+				if !isIdentRune(rs[i]) && !unicode.IsDigit(rs[i]) && !strings.ContainsRune("-.[]", rs[i]) {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(rs[start:i])})
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma})
+			i++
+		default:
+			op := string(c)
+			if i+1 < len(rs) && inList(string(rs[i:i+2]), []string{"==", "!=", "<>", "<=", ">=", "&&", "||"}) {
+				op = string(rs[i : i+2])
+			}
+			i += len(op)
+			switch op {
+			case "<>":
+				op = "!="
+			case "=":
+				op = "=="
+			case "+", "-", "*", "/", "%", "<", ">", "!", "==", "!=", "<=", ">=", "&&", "||":
+			default:
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// isIdentRune checks whether the rune can start an identifier
+func isIdentRune(c rune) bool {
+	return unicode.IsLetter(c) || c == '_' || c == '$' || c == '@'
+}
+
+// exprParser is a recursive descent parser of expressions
+type exprParser struct {
+	tokens    []token
+	pos       int
+	separator string
+}
+
+// parseExpression parses the expression used in a computed column
+func parseExpression(in, separator string) (expr, error) {
+	tokens, err := lexExpression(in, separator)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens, separator: separator}
+	e, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected token in %s", in)
+	}
+	return e, nil
+}
+
+// precedences of the binary operators, lowest first
+var exprPrecedences = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// parseBinary parses the binary operators having at least the provided precedence
+func (p *exprParser) parseBinary(level int) (expr, error) {
+	if level == len(exprPrecedences) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOperator || !inList(t.text, exprPrecedences[level]) {
+			return x, nil
+		}
+		p.next()
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &binaryExpr{operator: t.text, x: x, y: y}
+	}
+}
+
+func (p *exprParser) parseUnary() (expr, error) {
+	if t := p.peek(); t.kind == tokenOperator && (t.text == "-" || t.text == "!") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{operator: t.text, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber, tokenString:
+		return &literalExpr{value: t.value}, nil
+	case tokenLParen:
+		e, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return e, nil
+	case tokenIdent:
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(strings.ToLower(t.text))
+		}
+		switch strings.ToLower(t.text) {
+		case "true":
+			return &literalExpr{value: true}, nil
+		case "false":
+			return &literalExpr{value: false}, nil
+		case "null":
+			return &literalExpr{value: nil}, nil
+		}
+		return &pathExpr{path: t.text, separator: p.separator}, nil
+	}
+	return nil, fmt.Errorf("unexpected end of expression")
+}
+
+func (p *exprParser) parseCall(name string) (expr, error) {
+	p.next() // (
+	var args []expr
+	if p.peek().kind == tokenRParen {
+		p.next()
+	} else {
+		for {
+			a, err := p.parseBinary(0)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, a)
+			t := p.next()
+			if t.kind == tokenRParen {
+				break
+			}
+			if t.kind != tokenComma {
+				return nil, fmt.Errorf("missing closing parenthesis in %s", name)
+			}
+		}
+	}
+
+	switch name {
+	case "if":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("if expects 3 arguments")
+		}
+		return &ifExpr{cond: args[0], then: args[1], otherwise: args[2]}, nil
+	case "coalesce":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("coalesce expects at least 1 argument")
+		}
+		return &coalesceExpr{args: args}, nil
+	}
+
+	fn, ok := exprFuncs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", name)
+	}
+	if len(args) < fn.minArgs || (fn.maxArgs >= 0 && len(args) > fn.maxArgs) {
+		return nil, fmt.Errorf("invalid number of arguments for %s", name)
+	}
+	return &callExpr{name: name, fn: fn, args: args}, nil
+}
+
+// inList checks whether s is present in list
+func inList(s string, list []string) bool {
+	for _, l := range list {
+		if s == l {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package gojsonq
+
+import (
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	row := map[string]interface{}{
+		"price": float64(12.5),
+		"qty":   float64(4),
+		"name":  " Tom ",
+		"nick":  nil,
+		"tags":  []interface{}{"a", "b"},
+		"user":  map[string]interface{}{"first-name": "John", "born": "1990-05-17T10:00:00Z"},
+		"flag":  true,
+	}
+
+	testCases := []struct {
+		tag       string
+		in        string
+		expected  interface{}
+		errExpect bool
+	}{
+		{tag: "arithmetic precedence", in: "price * qty + 2 * (3 - 1)", expected: float64(54)},
+		{tag: "unary minus and modulo", in: "-qty % 3", expected: float64(-1)},
+		{tag: "division by zero", in: "price / 0", errExpect: true},
+		{tag: "string concatenation", in: "'a' + \"b\"", expected: "ab"},
+		{tag: "nested path with dash", in: "upper(user.first-name)", expected: "JOHN"},
+		{tag: "array index", in: "tags.[1] == 'b'", expected: true},
+		{tag: "comparison and logical", in: "qty >= 4 && !(price < 10) || false", expected: true},
+		{tag: "single equal sign", in: "qty = 4", expected: true},
+		{tag: "missing path", in: "missing * 2", errExpect: true},
+		{tag: "invalid operands", in: "name * 2", errExpect: true},
+		{tag: "coalesce", in: "coalesce(nick, missing, name)", expected: " Tom "},
+		{tag: "if evaluates one branch", in: "if(qty > 10, price / 0, 'cheap')", expected: "cheap"},
+		{tag: "trim and lower", in: "lower(trim(name))", expected: "tom"},
+		{tag: "length", in: "length(tags)", expected: float64(2)},
+		{tag: "substr", in: "substr('gojsonq', 2, 4)", expected: "json"},
+		{tag: "replace", in: "replace('a-b-c', '-', '+')", expected: "a+b+c"},
+		{tag: "concat", in: "concat(name, nick, qty)", expected: " Tom 4"},
+		{tag: "contains", in: "contains('gojsonq', 'json')", expected: true},
+		{tag: "strict function with nil", in: "upper(nick)", expected: nil},
+		{tag: "round", in: "round(price / 3, 2)", expected: 4.17},
+		{tag: "floor, ceil and abs", in: "floor(price) + ceil(price) + abs(-1)", expected: float64(26)},
+		{tag: "pow and sqrt", in: "sqrt(pow(qty, 2))", expected: float64(4)},
+		{tag: "min and max", in: "max(price, qty, nick) - min(qty, 2)", expected: 10.5},
+		{tag: "date functions", in: "concat(year(user.born), '-', month(user.born), '-', day(user.born))", expected: "1990-5-17"},
+		{tag: "date format", in: "date(user.born, '02/01/2006')", expected: "17/05/1990"},
+		{tag: "unix", in: "unix('1970-01-02T00:00:00Z')", expected: float64(86400)},
+		{tag: "invalid date", in: "year(name)", errExpect: true},
+		{tag: "boolean literals", in: "if(flag == true, null, false)", expected: nil},
+	}
+
+	for _, tc := range testCases {
+		e, err := parseExpression(tc.in, defaultSeparator)
+		if err != nil {
+			t.Errorf("Tag: %s\nfailed to parse: %v", tc.tag, err)
+			continue
+		}
+		v, err := e.eval(row)
+		if tc.errExpect {
+			if err == nil {
+				t.Errorf("Tag: %s\nexpected an error", tc.tag)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Tag: %s\nunexpected error: %v", tc.tag, err)
+		}
+		assertInterface(t, tc.expected, v, tc.tag)
+	}
+}
+
+func TestParseExpression_invalid(t *testing.T) {
+	for _, in := range []string{
+		"price *",
+		"(price",
+		"upper(name",
+		"unknown(name)",
+		"upper(name, 1)",
+		"if(a, b)",
+		"coalesce()",
+		"'abc",
+		"price # 2",
+		"price qty",
+	} {
+		if _, err := parseExpression(in, defaultSeparator); err == nil {
+			t.Errorf("failed to catch invalid expression %s", in)
+		}
+	}
+}
+
+func TestParseExpression_custom_separator(t *testing.T) {
+	e, err := parseExpression("user->age * 2", "->")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	v, _ := e.eval(map[string]interface{}{"user": map[string]interface{}{"age": float64(20)}})
+	assertInterface(t, float64(40), v, "expression with custom separator")
+}
+
+func TestMakeColumn(t *testing.T) {
+	testCases := []struct {
+		in, node, alias string
+		computed        bool
+	}{
+		{in: "name", node: "name", alias: "name"},
+		{in: "user.name", node: "user.name", alias: "name"},
+		{in: "user.name as userName", node: "user.name", alias: "userName"},
+		{in: "first name", node: "first name", alias: "first name"},
+		{in: "first-name", node: "first-name", alias: "first-name"},
+		{in: "2020", node: "2020", alias: "2020"},
+		{in: "price * qty AS total", node: "price * qty", alias: "total", computed: true},
+		{in: "price*qty", node: "price*qty", alias: "price*qty", computed: true},
+		{in: "concat(a, ' as ', b) as label", node: "concat(a, ' as ', b)", alias: "label", computed: true},
+	}
+
+	for _, tc := range testCases {
+		c := makeColumn(tc.in, defaultSeparator)
+		if c.node != tc.node || c.alias != tc.alias || (c.expr != nil) != tc.computed {
+			t.Errorf("failed to make column from %s, got: %+v", tc.in, c)
+		}
+	}
+}
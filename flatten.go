@@ -0,0 +1,227 @@
+package gojsonq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unwind deconstructs the array at the provided path of each object and outputs one row per element,
+// the element replaces the array under the same path. Rows whose value is missing, null or an empty
+// array are dropped, non-array values are kept as they are. Like joins, Unwind is applied before the
+// Where/OrWhere clauses, so the unwound elements can be queried, sorted or grouped
+func (j *JSONQ) Unwind(path string) *JSONQ {
+	j.stages = append(j.stages, func(j *JSONQ, aa []interface{}) []interface{} {
+		parts := strings.Split(path, j.option.separator)
+		result := make([]interface{}, 0, len(aa))
+		for _, a := range aa {
+			vm, ok := a.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			v, err := getNestedValue(vm, path, j.option.separator)
+			if err != nil || v == nil {
+				continue
+			}
+			list, ok := v.([]interface{})
+			if !ok {
+				result = append(result, vm)
+				continue
+			}
+			for _, elm := range list {
+				result = append(result, withNestedValue(vm, parts, elm))
+			}
+		}
+		return result
+	})
+	return j
+}
+
+// Flatten flattens the nested arrays of a list up to the provided depth. e.g: [1,[2,[3]]] => [1,2,[3]] for depth 1
+func (j *JSONQ) Flatten(depth int) *JSONQ {
+	j.prepare()
+	if depth <= 0 {
+		return j.addError(fmt.Errorf("%d is invalid depth", depth))
+	}
+	if list, ok := j.jsonContent.([]interface{}); ok {
+		j.jsonContent = flattenList(list, depth)
+	}
+	return j
+}
+
+// FlattenKeys converts the nested object (or each object of a list) to a single level object
+// whose keys are the joined paths of the values. e.g: {"a":{"b":[1]}} => {"a.b.[0]":1}
+// Empty sep uses the separator of JSONQ
+func (j *JSONQ) FlattenKeys(sep string) *JSONQ {
+	j.prepare()
+	if sep == "" {
+		sep = j.option.separator
+	}
+	switch v := j.jsonContent.(type) {
+	case map[string]interface{}:
+		j.jsonContent = flattenKeys(v, sep)
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, a := range v {
+			if vm, ok := a.(map[string]interface{}); ok {
+				result = append(result, flattenKeys(vm, sep))
+			} else {
+				result = append(result, a)
+			}
+		}
+		j.jsonContent = result
+	}
+	return j
+}
+
+// Unflatten reverses FlattenKeys and builds the nested object (or each object of a list) from the joined paths.
+// Empty sep uses the separator of JSONQ
+func (j *JSONQ) Unflatten(sep string) *JSONQ {
+	j.prepare()
+	if sep == "" {
+		sep = j.option.separator
+	}
+	switch v := j.jsonContent.(type) {
+	case map[string]interface{}:
+		nv, err := unflattenKeys(v, sep)
+		if err != nil {
+			return j.addError(err)
+		}
+		j.jsonContent = nv
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, a := range v {
+			if vm, ok := a.(map[string]interface{}); ok {
+				nv, err := unflattenKeys(vm, sep)
+				if err != nil {
+					return j.addError(err)
+				}
+				result = append(result, nv)
+			} else {
+				result = append(result, a)
+			}
+		}
+		j.jsonContent = result
+	}
+	return j
+}
+
+// withNestedValue returns a copy of node where the value of the path is replaced by v, the path is
+// resolved like getNestedValue does. Only the maps and arrays along the path are copied, other values
+// are shared with node
+func withNestedValue(node interface{}, parts []string, v interface{}) interface{} {
+	if len(parts) == 0 {
+		return v
+	}
+	if isIndex(parts[0]) {
+		list, ok := node.([]interface{})
+		if !ok {
+			return withNestedValue(node, parts[1:], v)
+		}
+		cl := append(make([]interface{}, 0, len(list)), list...)
+		if i, err := getIndex(parts[0]); err == nil && i >= 0 && i < len(cl) {
+			cl[i] = withNestedValue(cl[i], parts[1:], v)
+		}
+		return cl
+	}
+	m, _ := node.(map[string]interface{})
+	cm := make(map[string]interface{}, len(m))
+	for k, mv := range m {
+		cm[k] = mv
+	}
+	cm[parts[0]] = withNestedValue(m[parts[0]], parts[1:], v)
+	return cm
+}
+
+// flattenList flattens the nested arrays up to depth
+func flattenList(list []interface{}, depth int) []interface{} {
+	result := make([]interface{}, 0, len(list))
+	for _, v := range list {
+		if nested, ok := v.([]interface{}); ok && depth > 0 {
+			result = append(result, flattenList(nested, depth-1)...)
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// flattenKeys converts a nested map to a single level map, array indexes are written as [n]
+func flattenKeys(m map[string]interface{}, sep string) map[string]interface{} {
+	result := map[string]interface{}{}
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			if len(val) == 0 && prefix != "" {
+				result[prefix] = val
+			}
+			for k, mv := range val {
+				walk(joinKey(prefix, k, sep), mv)
+			}
+		case []interface{}:
+			if len(val) == 0 {
+				result[prefix] = val
+			}
+			for i, lv := range val {
+				walk(joinKey(prefix, indexSegment(i), sep), lv)
+			}
+		default:
+			result[prefix] = val
+		}
+	}
+	walk("", m)
+	return result
+}
+
+// joinKey joins the prefix and the key using sep
+func joinKey(prefix, key, sep string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + sep + key
+}
+
+// unflattenKeys builds a nested value from a single level map produced by flattenKeys. flattenKeys writes
+// at least one key per array element, so an index beyond the number of keys is reported instead of
+// allocating the array
+func unflattenKeys(m map[string]interface{}, sep string) (interface{}, error) {
+	keys := sortedKeys(m)
+
+	var root interface{} = map[string]interface{}{}
+	for _, k := range keys {
+		parts := strings.Split(k, sep)
+		for _, p := range parts {
+			if i, err := getIndex(p); err == nil && i >= len(m) {
+				return nil, &PathError{Path: k, Segment: p, Err: ErrIndexOutOfRange}
+			}
+		}
+		root = unflattenInsert(root, parts, m[k])
+	}
+	return root, nil
+}
+
+// unflattenInsert sets v to the path of node, creating the missing maps and arrays
+func unflattenInsert(node interface{}, parts []string, v interface{}) interface{} {
+	if len(parts) == 0 {
+		return v
+	}
+	if isIndex(parts[0]) {
+		if indx, err := getIndex(parts[0]); err == nil && indx >= 0 {
+			if m, ok := node.(map[string]interface{}); ok && len(m) > 0 {
+				return node // a non empty object can not be turned into an array
+			}
+			arr, _ := node.([]interface{})
+			for len(arr) <= indx {
+				arr = append(arr, nil)
+			}
+			arr[indx] = unflattenInsert(arr[indx], parts[1:], v)
+			return arr
+		}
+	}
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{}
+	}
+	m[parts[0]] = unflattenInsert(m[parts[0]], parts[1:], v)
+	return m
+}
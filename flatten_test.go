@@ -0,0 +1,93 @@
+package gojsonq
+
+import (
+	"errors"
+	"testing"
+)
+
+const jsonStrCarts = `[
+	{"id":1,"user":"john","items":[{"sku":"a","qty":2},{"sku":"b","qty":1}]},
+	{"id":2,"user":"ethan","items":[{"sku":"a","qty":5}]},
+	{"id":3,"user":"tom","items":[]},
+	{"id":4,"user":"jerry"},
+	{"id":5,"user":"abby","items":{"sku":"c","qty":1}}
+]`
+
+func TestJSONQ_Unwind(t *testing.T) {
+	jq := New().FromString(jsonStrCarts).Unwind("items")
+	expected := `[{"id":1,"items":{"qty":2,"sku":"a"},"user":"john"},{"id":1,"items":{"qty":1,"sku":"b"},"user":"john"},{"id":2,"items":{"qty":5,"sku":"a"},"user":"ethan"},{"id":5,"items":{"qty":1,"sku":"c"},"user":"abby"}]`
+	assertJSON(t, jq.Get(), expected, "unwind array field")
+}
+
+func TestJSONQ_Unwind_with_where_sort_and_group(t *testing.T) {
+	out := New().FromString(jsonStrCarts).
+		Unwind("items").
+		WhereEqual("items.sku", "a").
+		SortBy("items.qty", "desc").
+		Pluck("user")
+	assertJSON(t, out, `["ethan","john"]`, "unwind composes with where and sort")
+
+	grp := New().FromString(jsonStrCarts).Unwind("items").GroupBy("items.sku").Get()
+	assertJSON(t, grp, `{"a":[{"id":1,"items":{"qty":2,"sku":"a"},"user":"john"},{"id":2,"items":{"qty":5,"sku":"a"},"user":"ethan"}],"b":[{"id":1,"items":{"qty":1,"sku":"b"},"user":"john"}],"c":[{"id":5,"items":{"qty":1,"sku":"c"},"user":"abby"}]}`, "unwind composes with group by")
+}
+
+func TestJSONQ_Unwind_nested_path(t *testing.T) {
+	jq := New().FromString(`[{"id":1,"order":{"no":7,"lines":[1,2]}}]`)
+	assertJSON(t, jq.Unwind("order.lines").Get(), `[{"id":1,"order":{"lines":1,"no":7}},{"id":1,"order":{"lines":2,"no":7}}]`, "unwind nested path")
+	assertJSON(t, jq.Reset().Get(), `[{"id":1,"order":{"lines":[1,2],"no":7}}]`, "unwind must not modify the source")
+}
+
+func TestJSONQ_Unwind_path_with_index(t *testing.T) {
+	jq := New().FromString(`[{"id":1,"items":[{"sub":[1,2]},{"sub":[3]}]}]`)
+	expected := `[{"id":1,"items":[{"sub":1},{"sub":[3]}]},{"id":1,"items":[{"sub":2},{"sub":[3]}]}]`
+	assertJSON(t, jq.Unwind("items.[0].sub").Get(), expected, "unwind path with index")
+	assertJSON(t, jq.Reset().Get(), `[{"id":1,"items":[{"sub":[1,2]},{"sub":[3]}]}]`, "unwind path with index must not modify the source")
+}
+
+func TestJSONQ_Flatten(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		depth    int
+		expected string
+	}{
+		{tag: "depth 1", depth: 1, expected: `[1,2,3,[4,[5]],6]`},
+		{tag: "depth 2", depth: 2, expected: `[1,2,3,4,[5],6]`},
+		{tag: "deeper than the list", depth: 10, expected: `[1,2,3,4,5,6]`},
+	}
+
+	for _, tc := range testCases {
+		jq := New().FromString(`{"list":[1,[2,3,[4,[5]]],[],6]}`).From("list").Flatten(tc.depth)
+		assertJSON(t, jq.Get(), tc.expected, tc.tag)
+	}
+
+	jq := New().FromString(`[1,[2]]`).Flatten(0)
+	if jq.Error() == nil {
+		t.Error("failed to catch invalid depth")
+	}
+}
+
+func TestJSONQ_FlattenKeys(t *testing.T) {
+	jq := New().FromString(`{"name":{"first":"tom"},"tags":["a","b"],"meta":{},"list":[]}`).FlattenKeys("")
+	assertJSON(t, jq.Get(), `{"list":[],"meta":{},"name.first":"tom","tags.[0]":"a","tags.[1]":"b"}`, "flatten object using default separator")
+
+	jq = New().FromString(jsonStrUsers).From("users").Limit(1).FlattenKeys("_")
+	assertJSON(t, jq.Get(), `[{"id":1,"name_first":"John","name_last":"Ramboo"}]`, "flatten list of objects using custom separator")
+}
+
+func TestJSONQ_Unflatten(t *testing.T) {
+	jq := New().FromString(`{"list":[],"meta":{},"name.first":"tom","tags.[0]":"a","tags.[1]":"b","a.[1].b":1}`).Unflatten("")
+	assertJSON(t, jq.Get(), `{"a":[null,{"b":1}],"list":[],"meta":{},"name":{"first":"tom"},"tags":["a","b"]}`, "unflatten object")
+
+	jq = New().FromString(jsonStr).FlattenKeys("/").Unflatten("/")
+	assertInterface(t, New().FromString(jsonStr).Get(), jq.Get(), "flatten and unflatten round trip")
+}
+
+func TestJSONQ_Unflatten_index_out_of_range(t *testing.T) {
+	for _, in := range []string{`{"a.[50000000]":1}`, `[{"a.[0]":1},{"b.[2]":1,"c":2}]`} {
+		jq := New().FromString(in).Unflatten("")
+		var pe *PathError
+		if !errors.As(jq.Error(), &pe) || !errors.Is(pe, ErrIndexOutOfRange) {
+			t.Errorf("%s: expected index out of range, got: %v", in, jq.Error())
+		}
+	}
+}
@@ -0,0 +1,82 @@
+//go:build go1.16
+// +build go1.16
+
+package gojsonq
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// FromFS reads the json content of the file name from fsys, e.g: an embed.FS or a fstest.MapFS
+func (j *JSONQ) FromFS(fsys fs.FS, name string) *JSONQ {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return j.addError(err)
+	}
+	defer f.Close()
+	return j.Reader(f)
+}
+
+// FromGlob reads the json content of every file of fsys matching pattern as a single list,
+// each file is an element of the list in the lexical order of the names. When sourceKey is
+// given, the name of the file is added to its content under sourceKey, the content of each
+// file must be an object then. The maximum input size applies to the total size of the files.
+// e.g: FromGlob(fsys, "data/*.json", "_source")
+func (j *JSONQ) FromGlob(fsys fs.FS, pattern string, sourceKey ...string) *JSONQ {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return j.addError(err)
+	}
+
+	list := make([]interface{}, 0, len(names))
+	var total int64
+	for _, name := range names {
+		v, size, err := j.decodeFile(fsys, name)
+		if err != nil {
+			return j.addError(fmt.Errorf("%s: %w", name, err))
+		}
+		if total += size; j.option.maxInputSize > 0 && total > j.option.maxInputSize {
+			return j.addError(fmt.Errorf("%w of %d bytes", ErrInputTooLarge, j.option.maxInputSize))
+		}
+		if len(sourceKey) > 0 {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return j.addError(fmt.Errorf("%s: content is not an object to add %s", name, sourceKey[0]))
+			}
+			m[sourceKey[0]] = name
+		}
+		list = append(list, v)
+	}
+
+	j.raw = nil
+	j.rootJSONContent = list
+	j.jsonContent = j.rootJSONContent
+	return j
+}
+
+// decodeFile decodes the json content of the file name, it returns the size of the content
+func (j *JSONQ) decodeFile(fsys fs.FS, name string) (interface{}, int64, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	r, err := j.decompress(f)
+	if err != nil {
+		return nil, 0, err
+	}
+	raw, err := j.readAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := j.checkLimits(raw); err != nil {
+		return nil, 0, err
+	}
+	var v interface{}
+	if err := j.option.decoder.Decode(raw, &v); err != nil {
+		return nil, 0, newDecodeError(raw, err)
+	}
+	return v, int64(len(raw)), nil
+}
@@ -0,0 +1,91 @@
+//go:build go1.16
+// +build go1.16
+
+package gojsonq
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+var testFS = fstest.MapFS{
+	"data/items.json":   {Data: []byte(jsonStr)},
+	"data/users/a.json": {Data: []byte(`{"name":"a","age":30}`)},
+	"data/users/b.json": {Data: []byte(`{"name":"b","age":20}`)},
+	"data/users/c.txt":  {Data: []byte(`{"name":"c","age":10}`)},
+	"data/list.json":    {Data: []byte(`[1,2]`)},
+	"data/bad/x.json":   {Data: []byte(`{"name":`)},
+}
+
+func TestJSONQ_FromFS(t *testing.T) {
+	jq := New().FromFS(testFS, "data/items.json").From("vendor.name")
+	assertJSON(t, jq.Get(), `"Star Trek"`, "FromFS")
+
+	jq = New().FromFS(testFS, "data/missing.json")
+	if jq.Error() == nil {
+		t.Error("failed to catch missing file")
+	}
+}
+
+func TestJSONQ_FromGlob(t *testing.T) {
+	testCases := []struct {
+		tag       string
+		pattern   string
+		sourceKey []string
+		expected  string
+	}{
+		{
+			tag:      "files as a list",
+			pattern:  "data/users/*.json",
+			expected: `[{"age":30,"name":"a"},{"age":20,"name":"b"}]`,
+		},
+		{
+			tag:       "files annotated with their name",
+			pattern:   "data/users/*.json",
+			sourceKey: []string{"file"},
+			expected:  `[{"age":30,"file":"data/users/a.json","name":"a"},{"age":20,"file":"data/users/b.json","name":"b"}]`,
+		},
+		{
+			tag:      "no match",
+			pattern:  "data/*.csv",
+			expected: `[]`,
+		},
+	}
+	for _, tc := range testCases {
+		jq := New().FromGlob(testFS, tc.pattern, tc.sourceKey...)
+		assertJSON(t, jq.Get(), tc.expected, tc.tag)
+	}
+
+	// the list can be queried like any other content
+	jq := New().FromGlob(testFS, "data/users/*.json", "file").Where("age", "<", 25).Pluck("file")
+	assertJSON(t, jq, `["data/users/b.json"]`, "FromGlob query")
+}
+
+func TestJSONQ_FromGlob_errors(t *testing.T) {
+	jq := New().FromGlob(testFS, "data/bad/*.json")
+	var de *DecodeError
+	if !errors.As(jq.Error(), &de) {
+		t.Errorf("expected a DecodeError, got: %v", jq.Error())
+	}
+
+	if jq := New().FromGlob(testFS, "data/[", "file"); jq.Error() == nil {
+		t.Error("failed to catch invalid pattern")
+	}
+	if jq := New().FromGlob(testFS, "data/list.json", "file"); jq.Error() == nil {
+		t.Error("failed to catch non object content")
+	}
+	jq = New(WithMaxInputSize(30)).FromGlob(testFS, "data/users/*.json")
+	if !errors.Is(jq.Error(), ErrInputTooLarge) {
+		t.Errorf("expected ErrInputTooLarge, got: %v", jq.Error())
+	}
+}
+
+func TestJSONQ_FromGlob_decompression(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.json":    {Data: []byte(`{"id":1}`)},
+		"b.json.gz": {Data: gzipData(t, `{"id":2}`)},
+	}
+	jq := New().FromGlob(fsys, "*.json*")
+	assertJSON(t, jq.Get(), `[{"id":1},{"id":2}]`, "FromGlob of compressed files")
+}
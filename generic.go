@@ -0,0 +1,72 @@
+//go:build go1.18
+// +build go1.18
+
+package gojsonq
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// GetAs returns the result of the query converted to T. e.g: GetAs[[]Item](jq.From("items"))
+func GetAs[T any](j *JSONQ) (T, error) {
+	v := j.Get()
+	return as[T](v, j.Error())
+}
+
+// FindAs returns the value of the exact matching path converted to T. e.g: FindAs[string](jq, "vendor.name")
+func FindAs[T any](j *JSONQ, path string) (T, error) {
+	v := j.Find(path)
+	return as[T](v, j.Error())
+}
+
+// PluckAs builds a list of the values of property converted to T. e.g: PluckAs[float64](jq.From("items"), "price")
+func PluckAs[T any](j *JSONQ, property string) ([]T, error) {
+	v := j.Pluck(property)
+	if err := j.Error(); err != nil {
+		return nil, err
+	}
+	list, _ := v.([]interface{})
+	tt := make([]T, len(list))
+	for i, e := range list {
+		if err := convert(e, reflect.ValueOf(&tt[i]).Elem()); err != nil {
+			return nil, fmt.Errorf("gojsonq: %w", err)
+		}
+	}
+	return tt, nil
+}
+
+// Rows converts every row matched by the query to T, the rows are evaluated lazily. e.g: Rows[Item](jq.From("items"))
+func Rows[T any](j *JSONQ) ([]T, error) {
+	tt := make([]T, 0)
+	it := j.Iter()
+	if it.single {
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("gojsonq: %T is not a list of rows", j.jsonContent)
+	}
+	for it.Next() {
+		var t T
+		if err := convert(it.value, reflect.ValueOf(&t).Elem()); err != nil {
+			return nil, fmt.Errorf("gojsonq: %w", err)
+		}
+		tt = append(tt, t)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return tt, nil
+}
+
+// as converts v to T unless err is not nil
+func as[T any](v interface{}, err error) (T, error) {
+	var t T
+	if err != nil {
+		return t, err
+	}
+	if err := convert(v, reflect.ValueOf(&t).Elem()); err != nil {
+		return t, fmt.Errorf("gojsonq: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,82 @@
+//go:build go1.18
+// +build go1.18
+
+package gojsonq
+
+import (
+	"errors"
+	"testing"
+)
+
+type genericItem struct {
+	ID    int     `json:"id"`
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+	Key   *int    `json:"key"`
+}
+
+func TestGetAs(t *testing.T) {
+	items, err := GetAs[[]genericItem](New().FromString(jsonStr).From("vendor.items").Where("price", ">", 1300))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertInterface(t, items, []genericItem{{ID: 1, Name: "MacBook Pro 13 inch retina", Price: 1350}, {ID: 2, Name: "MacBook Pro 15 inch retina", Price: 1700}}, "get as struct list")
+
+	m, err := GetAs[map[string]interface{}](New().FromString(jsonStr).From("vendor.items.[0]"))
+	if err != nil || m["name"] != "MacBook Pro 13 inch retina" {
+		t.Errorf("failed to get as map, got: %v %v", m, err)
+	}
+
+	if _, err := GetAs[[]genericItem](New().FromString(jsonStr).From("vendor.invalid")); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("expected invalid node, got: %v", err)
+	}
+	var te *TypeError
+	if _, err := GetAs[string](New().FromString(jsonStr).From("vendor.items")); !errors.As(err, &te) {
+		t.Errorf("expected type error, got: %v", err)
+	}
+}
+
+func TestFindAs(t *testing.T) {
+	name, err := FindAs[string](New().FromString(jsonStr), "vendor.name")
+	if err != nil || name != "Star Trek" {
+		t.Errorf("expected Star Trek, got: %v %v", name, err)
+	}
+	price, err := FindAs[int](New().FromString(jsonStr), "vendor.items.[1].price")
+	if err != nil || price != 1700 {
+		t.Errorf("expected 1700, got: %v %v", price, err)
+	}
+	if _, err := FindAs[int8](New().FromString(jsonStr), "vendor.items.[1].price"); err == nil {
+		t.Error("failed to catch overflow")
+	}
+}
+
+func TestPluckAs(t *testing.T) {
+	prices, err := PluckAs[float64](New().FromString(jsonStr).From("vendor.items"), "price")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertInterface(t, prices, []float64{1350, 1700, 1200, 850, 850, 950, 850}, "pluck as float64")
+
+	if _, err := PluckAs[int](New().FromString(jsonStr).From("vendor.items"), "name"); err == nil {
+		t.Error("failed to catch type mismatch")
+	}
+}
+
+func TestRows(t *testing.T) {
+	rows, err := Rows[genericItem](New(WithMissingKeyPolicy(MissingKeyIgnore)).FromString(jsonStr).From("vendor.items").WhereNotNil("key"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ID != 5 || rows[0].Key == nil || *rows[0].Key != 2300 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+
+	rows, err = Rows[genericItem](New().FromString(jsonStr).From("vendor.items").WhereEqual("price", 1))
+	if err != nil || rows == nil || len(rows) != 0 {
+		t.Errorf("expected no rows, got: %v %v", rows, err)
+	}
+
+	if _, err := Rows[genericItem](New().FromString(jsonStr).From("vendor")); err == nil {
+		t.Error("failed to catch object instead of list")
+	}
+}
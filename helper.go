@@ -1,6 +1,7 @@
 package gojsonq
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -22,13 +23,13 @@ func isIndex(in string) bool {
 
 func getIndex(in string) (int, error) {
 	if !isIndex(in) {
-		return -1, fmt.Errorf("invalid index")
+		return -1, ErrInvalidIndex
 	}
 	is := strings.TrimLeft(in, "[")
 	is = strings.TrimRight(is, "]")
 	oint, err := strconv.Atoi(is)
 	if err != nil {
-		return -1, err
+		return -1, ErrInvalidIndex
 	}
 	return oint, nil
 }
@@ -37,6 +38,21 @@ func toString(v interface{}) string {
 	return fmt.Sprintf("%v", v)
 }
 
+// hashKey builds a map key for a value so that equal values produce the same key,
+// numeric values are converted to float64 and strings never collide with numbers
+func hashKey(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		if bb, err := json.Marshal(v); err == nil {
+			return "json:" + string(bb)
+		}
+	}
+	if f, ok := toFloat64(v); ok {
+		v = f
+	}
+	return fmt.Sprintf("%T:%v", v, v)
+}
+
 // toFloat64 converts interface{} value to float64 if value is numeric else return false
 func toFloat64(v interface{}) (float64, bool) {
 	var f float64
@@ -63,6 +79,16 @@ func toFloat64(v interface{}) (float64, bool) {
 	return f, flag
 }
 
+// isEqual checks whether x, y are deeply equal, numeric values are compared as float64
+func isEqual(x, y interface{}) bool {
+	if fx, ok := toFloat64(x); ok {
+		if fy, ok := toFloat64(y); ok {
+			return fx == fy
+		}
+	}
+	return reflect.DeepEqual(x, y)
+}
+
 // sortList sorts a list of interfaces
 func sortList(list []interface{}, asc bool) []interface{} {
 	var ss []string
@@ -105,14 +131,18 @@ func sortList(list []interface{}, asc bool) []interface{} {
 type sortMap struct {
 	data      interface{}
 	key       string
+	parts     []string
 	desc      bool
 	separator string
-	errs      []error
+	canceled  func() bool // checked periodically, nothing is compared once it returns true
+	compared  int
+	stopped   bool
 }
 
 // Sort sorts the slice of maps
 func (s *sortMap) Sort(data interface{}) {
 	s.data = data
+	s.parts = strings.Split(s.key, s.separator)
 	sort.Sort(s)
 }
 
@@ -136,20 +166,22 @@ func (s *sortMap) Swap(i, j int) {
 // Less satisfies the sort.Interface
 // This will work for string/float64 only
 func (s *sortMap) Less(i, j int) (res bool) {
+	if s.canceled != nil && !s.stopped && s.compared%cancelCheckInterval == 0 {
+		s.stopped = s.canceled()
+	}
+	s.compared++
+	if s.stopped {
+		return false
+	}
 	list := reflect.ValueOf(s.data)
 	x := list.Index(i).Interface()
 	y := list.Index(j).Interface()
 
 	// compare nested values
-	if strings.Contains(s.key, s.separator) {
-		xv, errX := getNestedValue(x, s.key, s.separator)
-		if errX != nil {
-			s.errs = append(s.errs, errX)
-		}
-		yv, errY := getNestedValue(y, s.key, s.separator)
-		if errY != nil {
-			s.errs = append(s.errs, errY)
-		}
+	if len(s.parts) > 1 {
+		// missing keys are reported by the caller once per row
+		xv, _ := getNestedValueParts(x, s.parts)
+		yv, _ := getNestedValueParts(y, s.parts)
 		res = s.compare(xv, yv)
 	}
 
@@ -158,7 +190,8 @@ func (s *sortMap) Less(i, j int) (res bool) {
 		return
 	}
 	yv := y.(map[string]interface{})
-	if mvx, ok := xv[s.key]; ok {
+	// a missing key is compared as null, the rows are kept only when the policy treats them as null
+	if mvx, ok := xv[s.key]; ok || len(s.parts) == 1 {
 		mvy := yv[s.key]
 		res = s.compare(mvx, mvy)
 	}
@@ -168,6 +201,14 @@ func (s *sortMap) Less(i, j int) (res bool) {
 
 // compare compare two values
 func (s *sortMap) compare(x, y interface{}) (res bool) {
+	// null is less than any other value
+	if x == nil || y == nil {
+		if s.desc {
+			return x != nil && y == nil
+		}
+		return x == nil && y != nil
+	}
+
 	if mfv, ok := x.(float64); ok {
 		if mvy, oky := y.(float64); oky {
 			if s.desc {
@@ -191,19 +232,24 @@ func (s *sortMap) compare(x, y interface{}) (res bool) {
 
 // getNestedValue fetch nested value from node
 func getNestedValue(input interface{}, node, separator string) (interface{}, error) {
-	pp := strings.Split(node, separator)
+	v, err := getNestedValueParts(input, strings.Split(node, separator))
+	return v, withPath(err, node)
+}
+
+// getNestedValueParts fetch nested value from the node already split by separator
+func getNestedValueParts(input interface{}, pp []string) (interface{}, error) {
 	for _, n := range pp {
 		if isIndex(n) {
 			// find slice/array
 			if arr, ok := input.([]interface{}); ok {
 				indx, err := getIndex(n)
 				if err != nil {
-					return input, err
+					return input, &PathError{Segment: n, Err: err}
 				}
 				arrLen := len(arr)
 				if arrLen == 0 ||
 					indx > arrLen-1 {
-					return empty, errors.New("empty array")
+					return empty, &PathError{Segment: n, Err: ErrIndexOutOfRange}
 				}
 				input = arr[indx]
 			}
@@ -222,7 +268,7 @@ func getNestedValue(input interface{}, node, separator string) (interface{}, err
 			}
 
 			if !validNode {
-				return empty, fmt.Errorf("invalid node name %s", n)
+				return empty, &PathError{Segment: n, Err: ErrInvalidNode}
 			}
 		}
 	}
@@ -251,6 +297,56 @@ func makeAlias(in, separator string) (string, string) {
 	return in, in
 }
 
+// column describes a property of the Select clause
+type column struct {
+	node, alias string
+	parts       []string                      // node split by separator
+	expr        expr                          // computed value of an expression, e.g: price * qty
+	fn          func(row *Result) interface{} // computed value of a SelectFunc
+}
+
+// makeColumn builds a column from a Select property. Properties like "price * qty as total" or
+// "upper(name)" are computed unless the row has a node of the same name, e.g: "a/b", the others
+// are node names handled by makeAlias
+func makeColumn(property, separator string) column {
+	in := strings.NewReplacer(" As ", " as ", " AS ", " as ").Replace(property)
+	node, alias := strings.TrimSpace(in), ""
+	if i := strings.LastIndex(in, " as "); i >= 0 {
+		node, alias = strings.TrimSpace(in[:i]), strings.TrimSpace(in[i+len(" as "):])
+	}
+	if e, err := parseExpression(node, separator); err == nil {
+		switch e.(type) {
+		case *pathExpr, *literalExpr:
+		default:
+			if alias == "" {
+				alias = node
+			}
+			return column{node: node, alias: alias, parts: strings.Split(node, separator), expr: e}
+		}
+	}
+	node, alias = makeAlias(property, separator)
+	return column{node: node, alias: alias, parts: strings.Split(node, separator)}
+}
+
+// value returns the value of the column for the row
+func (c column) value(row interface{}) (interface{}, error) {
+	switch {
+	case c.fn != nil:
+		return c.fn(NewResult(row)), nil
+	case c.expr != nil:
+		// a node named like the expression takes precedence
+		if v, err := getNestedValueParts(row, c.parts); err == nil {
+			return v, nil
+		}
+		v, err := c.expr.eval(row)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.alias, err)
+		}
+		return v, nil
+	}
+	return getNestedValueParts(row, c.parts)
+}
+
 // length return length of strings/array/map
 func length(v interface{}) (int, error) {
 	switch val := v.(type) {
@@ -264,3 +360,13 @@ func length(v interface{}) (int, error) {
 		return -1, errors.New("invalid type for length")
 	}
 }
+
+// sortedKeys returns the keys of the object in ascending order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,190 @@
+package gojsonq
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// index kinds
+const (
+	indexHash   = "hash"
+	indexSorted = "sorted"
+)
+
+// index describes a secondary index over the objects of a list
+type index struct {
+	path, keyPath string
+	list          []interface{}    // indexed list, the index is used only for this exact list
+	hash          map[string][]int // positions of the rows by the hash key of their value
+	sorted        []indexEntry     // numeric values sorted in ascending order
+	missing       []int            // positions of the objects missing the key
+	unsorted      []int            // positions of the objects with a non numeric value
+}
+
+// indexEntry describes a numeric value of the sorted index
+type indexEntry struct {
+	value float64
+	pos   int
+}
+
+// CreateIndex builds an index of the list available at path (empty for the root) using the value of keyPath.
+// A hash index serves equality and in lookups, a sorted index serves range lookups on numeric values.
+// Indexes are used automatically by queries with a single group of AND clauses (no OrWhere) over the
+// indexed list and survive Copy, Reset and Document. A query reports the same errors with or without an
+// index, so the index is not used for several clauses unless the policies ignore the row errors.
+// e.g: CreateIndex("vendor.items", "id")
+func (j *JSONQ) CreateIndex(path, keyPath string) *JSONQ {
+	v := j.rootJSONContent
+	if path != "" {
+		var err error
+		if v, err = getNestedValue(v, path, j.option.separator); err != nil {
+			return j.addError(err)
+		}
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return j.addError(fmt.Errorf("%s is not a list to index", path))
+	}
+
+	idx := &index{path: path, keyPath: keyPath, list: list, hash: map[string][]int{}}
+	parts := strings.Split(keyPath, j.option.separator)
+	for i, a := range list {
+		if _, ok := a.(map[string]interface{}); !ok {
+			continue
+		}
+		kv, err := getNestedValueParts(a, parts)
+		if err != nil {
+			idx.missing = append(idx.missing, i)
+			continue
+		}
+		idx.hash[hashKey(kv)] = append(idx.hash[hashKey(kv)], i)
+		if f, ok := kv.(float64); ok {
+			idx.sorted = append(idx.sorted, indexEntry{value: f, pos: i})
+		} else {
+			idx.unsorted = append(idx.unsorted, i)
+		}
+	}
+	sort.Slice(idx.sorted, func(a, b int) bool {
+		return idx.sorted[a].value < idx.sorted[b].value
+	})
+
+	// never append in place, the indexes may be shared with copies
+	j.indexes = append(j.indexes[:len(j.indexes):len(j.indexes)], idx)
+	return j
+}
+
+// kind returns the kind of index serving the operator, empty if the operator is not supported
+func (idx *index) kind(operator string) string {
+	switch operator {
+	case operatorEq, operatorEqEng, operatorIn:
+		return indexHash
+	case operatorGt, operatorGtEng, operatorGtE, operatorGtEEng, operatorLt, operatorLtEng, operatorLtE, operatorLtEEng:
+		return indexSorted
+	}
+	return ""
+}
+
+// lookup returns the positions of the candidate rows for the predicate in ascending order
+func (idx *index) lookup(p predicate) []int {
+	var pos []int
+	switch p.operator {
+	case operatorEq, operatorEqEng:
+		pos = idx.hash[hashKey(p.value)]
+	case operatorIn:
+		switch values := p.value.(type) {
+		case []string:
+			for _, v := range values {
+				pos = append(pos, idx.hash[hashKey(v)]...)
+			}
+		case []int:
+			for _, v := range values {
+				pos = append(pos, idx.hash[hashKey(v)]...)
+			}
+		case []float64:
+			for _, v := range values {
+				pos = append(pos, idx.hash[hashKey(v)]...)
+			}
+		}
+	default:
+		f, ok := toFloat64(p.value)
+		if !ok {
+			return []int{}
+		}
+		var from, to int
+		switch p.operator {
+		case operatorGt, operatorGtEng:
+			from, to = idx.search(func(v float64) bool { return v > f }), len(idx.sorted)
+		case operatorGtE, operatorGtEEng:
+			from, to = idx.search(func(v float64) bool { return v >= f }), len(idx.sorted)
+		case operatorLt, operatorLtEng:
+			from, to = 0, idx.search(func(v float64) bool { return v >= f })
+		default: // lte
+			from, to = 0, idx.search(func(v float64) bool { return v > f })
+		}
+		for _, e := range idx.sorted[from:to] {
+			pos = append(pos, e.pos)
+		}
+	}
+	return uniquePositions(pos)
+}
+
+// candidates returns the positions of the rows to visit for the predicate. The rows missing the key
+// or holding a non numeric value for a range lookup are visited as well unless the policies ignore them,
+// so that the result and the errors do not depend on the index
+func (j *JSONQ) candidates(idx *index, p predicate) []int {
+	pos := idx.lookup(p)
+	if j.option.missingKey != MissingKeyIgnore {
+		pos = append(pos, idx.missing...)
+	}
+	if idx.kind(p.operator) == indexSorted && j.option.typeMismatch != TypeMismatchIgnore {
+		pos = append(pos, idx.unsorted...)
+	}
+	return uniquePositions(pos)
+}
+
+// search returns the first position of the sorted index satisfying fn
+func (idx *index) search(fn func(v float64) bool) int {
+	return sort.Search(len(idx.sorted), func(i int) bool {
+		return fn(idx.sorted[i].value)
+	})
+}
+
+// findIndex returns an index able to serve one of the predicates of the list, the index is used
+// only when the queries contain a single group, otherwise the other groups need a full scan anyway
+func (j *JSONQ) findIndex(aa []interface{}, groups [][]predicate) (*index, predicate) {
+	if len(groups) != 1 {
+		return nil, predicate{}
+	}
+	// the other clauses have to be checked on every row when their errors are reported
+	reported := j.option.missingKey == MissingKeyError || j.option.typeMismatch == TypeMismatchError
+	if reported && len(groups[0]) > 1 {
+		return nil, predicate{}
+	}
+	for _, p := range groups[0] {
+		for _, idx := range j.indexes {
+			if idx.keyPath == p.key && idx.kind(p.operator) != "" && sameList(idx.list, aa) {
+				return idx, p
+			}
+		}
+	}
+	return nil, predicate{}
+}
+
+// sameList checks whether a and b are the same list (not only equal)
+func sameList(a, b []interface{}) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+// uniquePositions returns a sorted copy of the positions without duplicates, never nil
+func uniquePositions(pos []int) []int {
+	pp := append(make([]int, 0, len(pos)), pos...)
+	sort.Ints(pp)
+	out := pp[:0]
+	for _, p := range pp {
+		if len(out) == 0 || p != out[len(out)-1] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
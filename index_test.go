@@ -0,0 +1,150 @@
+package gojsonq
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJSONQ_CreateIndex(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		key      string
+		operator string
+		value    interface{}
+		expected string
+	}{
+		{tag: "equal", key: "price", operator: "=", value: 850, expected: `[4,5,null]`},
+		{tag: "equal string", key: "name", operator: "eq", value: "HP core i7", expected: `[6]`},
+		{tag: "in", key: "id", operator: "in", value: []int{2, 6, 9}, expected: `[2,6]`},
+		{tag: "greater than", key: "price", operator: ">", value: 1200, expected: `[1,2]`},
+		{tag: "greater than or equal", key: "price", operator: ">=", value: 1200, expected: `[1,2,3]`},
+		{tag: "less than", key: "price", operator: "lt", value: 950, expected: `[4,5,null]`},
+		{tag: "less than or equal", key: "price", operator: "<=", value: 950, expected: `[4,5,6,null]`},
+		{tag: "non numeric range", key: "price", operator: ">", value: "a", expected: `[]`},
+		{tag: "no match", key: "price", operator: "=", value: 1, expected: `[]`},
+	}
+
+	for _, tc := range testCases {
+		jq := New().FromString(jsonStr).CreateIndex("vendor.items", tc.key)
+		out := jq.From("vendor.items").Where(tc.key, tc.operator, tc.value).Pluck("id")
+		assertJSON(t, out, tc.expected, tc.tag)
+		if jq.Error() != nil {
+			t.Errorf("tag: %s, unexpected error: %v", tc.tag, jq.Error())
+		}
+
+		// the indexed query gives the same result as a full scan
+		scan := New().FromString(jsonStr).From("vendor.items").Where(tc.key, tc.operator, tc.value).Pluck("id")
+		assertInterface(t, scan, out, tc.tag+" full scan")
+	}
+}
+
+func TestJSONQ_CreateIndex_rechecks_other_clauses(t *testing.T) {
+	jq := New().FromString(jsonStr).CreateIndex("vendor.items", "price")
+	out := jq.From("vendor.items").WhereEqual("price", 850).WhereStartsWith("name", "HP").Pluck("id")
+	assertJSON(t, out, `[5,null]`, "indexed query with other clauses")
+
+	// OrWhere needs a full scan
+	jq.Reset()
+	out = jq.From("vendor.items").WhereEqual("price", 850).OrWhere("id", "=", 1).Pluck("id")
+	assertJSON(t, out, `[1,4,5,null]`, "indexed query with OrWhere")
+}
+
+func TestJSONQ_CreateIndex_survives_copy_and_document(t *testing.T) {
+	jq := New().FromString(jsonStr).CreateIndex("vendor.items", "id")
+	cp := jq.Copy().CreateIndex("vendor.items", "price")
+	if len(jq.indexes) != 1 || len(cp.indexes) != 2 {
+		t.Errorf("expected 1 and 2 indexes, got: %d and %d", len(jq.indexes), len(cp.indexes))
+	}
+	if cp.From("vendor.items").WhereEqual("id", 3).Explain().Index == nil {
+		t.Error("expected copy to use the index")
+	}
+
+	doc, _ := cp.Document()
+	out, _ := doc.Query().From("vendor.items").WhereEqual("price", 1700).Pluck("id")
+	assertJSON(t, out, `[2]`, "document with indexes")
+
+	pq, _ := NewQuery().From("vendor.items").Where("price", ">", 1300).Compile()
+	out, _ = pq.Run(doc)
+	assertJSON(t, out, `[{"id":1,"name":"MacBook Pro 13 inch retina","price":1350},{"id":2,"name":"MacBook Pro 15 inch retina","price":1700}]`, "prepared query with indexes")
+}
+
+func TestJSONQ_CreateIndex_errors(t *testing.T) {
+	testCases := []struct {
+		tag  string
+		path string
+	}{
+		{tag: "invalid node", path: "vendor.invalid"},
+		{tag: "not a list", path: "vendor.name"},
+	}
+	for _, tc := range testCases {
+		jq := New().FromString(jsonStr).CreateIndex(tc.path, "id")
+		if jq.Error() == nil {
+			t.Errorf("failed to catch %s", tc.tag)
+		}
+	}
+}
+
+func TestJSONQ_CreateIndex_root_list(t *testing.T) {
+	jq := New().FromString(`[{"id":1},{"id":2},{"name":"none"},3]`).CreateIndex("", "id")
+	assertJSON(t, jq.WhereEqual("id", 2).Get(), `[{"id":2}]`, "root list index")
+}
+
+func TestJSONQ_CreateIndex_missing_key_as_null(t *testing.T) {
+	data := `[{"id":1,"sku":"a"},{"id":2},{"id":3,"sku":null},{"id":4,"sku":"b"}]`
+	scan := New(WithMissingKeyPolicy(MissingKeyTreatAsNull)).FromString(data).WhereNil("sku").Pluck("id")
+	assertJSON(t, scan, `[2,3]`, "missing key as null full scan")
+
+	jq := New(WithMissingKeyPolicy(MissingKeyTreatAsNull)).FromString(data).CreateIndex("", "sku")
+	assertInterface(t, scan, jq.WhereNil("sku").Pluck("id"), "missing key as null indexed")
+}
+
+func TestJSONQ_CreateIndex_reports_row_errors(t *testing.T) {
+	data := `[{"p":1},{"p":"x"},{"q":3}]`
+	scan := New().FromString(data)
+	scanOut := scan.Where("p", ">", 0).Get()
+
+	jq := New().FromString(data).CreateIndex("", "p")
+	assertInterface(t, scanOut, jq.Where("p", ">", 0).Get(), "indexed query with row errors")
+	if len(scan.Errors()) != 2 || len(jq.Errors()) != 2 {
+		t.Errorf("expected 2 errors with and without index, got: %d and %d", len(scan.Errors()), len(jq.Errors()))
+	}
+
+	// the errors of the other clauses are reported for every row, the index is not used
+	jq = New().FromString(data).CreateIndex("", "p")
+	if jq.Where("p", ">", 0).WhereEqual("q", 3).Explain().Index != nil {
+		t.Error("expected a full scan for several clauses reporting row errors")
+	}
+	jq = New(WithMissingKeyPolicy(MissingKeyIgnore), WithTypeMismatchPolicy(TypeMismatchIgnore)).FromString(data).CreateIndex("", "p")
+	if jq.Where("p", ">", 0).WhereEqual("q", 3).Explain().Index == nil {
+		t.Error("expected the index to be used when the row errors are ignored")
+	}
+}
+
+func benchmarkItems(n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id":%d,"sku":"sku-%d"}`, i, i)
+	}
+	return `{"items":[` + strings.Join(items, ",") + `]}`
+}
+
+func Benchmark_CreateIndex_lookup(b *testing.B) {
+	jq := New().FromString(benchmarkItems(10000)).CreateIndex("items", "sku")
+	for n := 0; n < b.N; n++ {
+		jq.Reset()
+		if jq.From("items").WhereEqual("sku", "sku-5000").Count() != 1 {
+			b.Fail()
+		}
+	}
+}
+
+func Benchmark_full_scan_lookup(b *testing.B) {
+	jq := New().FromString(benchmarkItems(10000))
+	for n := 0; n < b.N; n++ {
+		jq.Reset()
+		if jq.From("items").WhereEqual("sku", "sku-5000").Count() != 1 {
+			b.Fail()
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package gojsonq
+
+import (
+	"fmt"
+	"time"
+)
+
+// Iterator iterates lazily over the result of a query. The where clauses, Distinct, Select, Offset and
+// Limit are applied row by row, so the iteration stops without processing the remaining rows. e.g:
+//
+//	it := jq.From("items").Where("price", ">", 100).Iter()
+//	for it.Next() {
+//		fmt.Println(it.Value())
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type Iterator struct {
+	j         *JSONQ
+	list      []interface{}
+	positions []int // candidates found by an index, nil to visit every row
+	next      int
+	groups    [][]predicate
+	columns   []column
+	seen      map[string]bool // distinct values
+	offset    int
+	limit     int
+	visited   int
+	matched   int
+	skipped   int
+	emitted   int
+	single    bool // the content is not a list and is the only value
+	done      bool
+	value     interface{}
+}
+
+// Iter returns an iterator over the result of the query
+func (j *JSONQ) Iter() *Iterator {
+	return j.iterator(j.offsetRecords, j.limitRecords)
+}
+
+// iterator returns an iterator over the result of the query using offset and limit
+func (j *JSONQ) iterator(offset, limit int) *Iterator {
+	if len(j.stages) > 0 {
+		j.processStages()
+	}
+	it := &Iterator{j: j, offset: offset, limit: limit}
+	aa, ok := j.jsonContent.([]interface{})
+	if !ok {
+		it.single = true
+		return it
+	}
+	it.list = aa
+
+	if offset < 0 {
+		j.addError(fmt.Errorf("%d is invalid offset", offset))
+		it.done = true
+	}
+	if limit < 0 {
+		j.addError(fmt.Errorf("%d is invalid limit", limit))
+		it.done = true
+	}
+
+	// the list is already filtered by the queries and Distinct, e.g: by SortBy
+	if j.filtered {
+		if !j.projected {
+			it.columns = append(j.columns(j.attributes), j.selectColumns...)
+		}
+		return it
+	}
+	it.groups = j.predicates
+	if it.groups == nil {
+		var err error
+		if it.groups, err = j.compileQueries(); err != nil {
+			j.addError(err)
+			it.done = true
+		}
+	}
+	if len(it.groups) > 0 {
+		if j.option.tracing {
+			j.traceScan(0, it.groups, time.Now())
+		}
+		if idx, p := j.findIndex(aa, it.groups); idx != nil {
+			it.positions = j.candidates(idx, p)
+		}
+	}
+	if j.distinctProperty != "" {
+		it.seen = map[string]bool{}
+	}
+	if !j.projected {
+		it.columns = append(j.columns(j.attributes), j.selectColumns...)
+	}
+	return it
+}
+
+// Next advances the iterator to the next value, it returns false when there is no more value or an error occurred
+func (it *Iterator) Next() bool {
+	it.value = nil
+	if it.done {
+		return false
+	}
+	if it.single {
+		it.done = true
+		it.value = it.j.jsonContent
+		return it.value != nil
+	}
+	if it.limit > 0 && it.emitted >= it.limit {
+		it.done = true
+		return false
+	}
+
+	j := it.j
+	if j.trace != nil && len(it.groups) > 0 {
+		defer j.traceScan(0, it.groups, time.Now())()
+	}
+	for {
+		i, ok := it.nextPosition()
+		if !ok || (it.visited%cancelCheckInterval == 0 && j.canceled()) {
+			it.done = true
+			return false
+		}
+		it.visited++
+		row := it.list[i]
+
+		if len(it.groups) > 0 {
+			if j.trace != nil {
+				j.trace.Rows++
+			}
+			vm, ok := row.(map[string]interface{})
+			if !ok || !j.matches(i, vm, it.groups) {
+				continue
+			}
+			if j.trace != nil {
+				j.trace.Matched++
+			}
+		}
+		if it.seen != nil && !it.distinct(row) {
+			continue
+		}
+		if len(it.columns) > 0 {
+			tmap, ok := j.projectRow(row, it.columns)
+			if !ok {
+				continue
+			}
+			row = tmap
+		}
+		if max := j.option.maxResultRows; max > 0 {
+			if it.matched++; it.matched > max {
+				j.addError(fmt.Errorf("%w of %d", ErrTooManyRows, max))
+				it.done = true
+				return false
+			}
+		}
+		if it.skipped < it.offset {
+			it.skipped++
+			continue
+		}
+
+		it.emitted++
+		it.value = row
+		return true
+	}
+}
+
+// nextPosition returns the position of the next row to visit
+func (it *Iterator) nextPosition() (int, bool) {
+	n := len(it.list)
+	if it.positions != nil {
+		n = len(it.positions)
+	}
+	if it.next >= n {
+		return 0, false
+	}
+	i := it.next
+	it.next++
+	if it.positions != nil {
+		return it.positions[i], true
+	}
+	return i, true
+}
+
+// distinct checks whether the distinct value of the row is seen for the first time
+func (it *Iterator) distinct(row interface{}) bool {
+	vm, ok := row.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	v, ok := it.j.lookup(vm, it.j.distinctProperty)
+	if !ok || it.seen[toString(v)] {
+		return false
+	}
+	it.seen[toString(v)] = true
+	return true
+}
+
+// Value returns the current value as Result instance
+func (it *Iterator) Value() *Result {
+	return it.j.result(it.value)
+}
+
+// Err returns the first error occurred while iterating
+func (it *Iterator) Err() error {
+	return it.j.contextError()
+}
+
+// Exists checks whether the query has at least one result, it stops at the first match
+func (j *JSONQ) Exists() bool {
+	return j.iterator(0, 0).Next()
+}
@@ -0,0 +1,167 @@
+package gojsonq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestJSONQ_Iter(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		query    func(jq *JSONQ) *JSONQ
+		expected string
+	}{
+		{
+			tag:      "where",
+			query:    func(jq *JSONQ) *JSONQ { return jq.Where("price", ">", 1000) },
+			expected: `[{"id":1,"name":"MacBook Pro 13 inch retina","price":1350},{"id":2,"name":"MacBook Pro 15 inch retina","price":1700},{"id":3,"name":"Sony VAIO","price":1200}]`,
+		},
+		{
+			tag:      "distinct and select",
+			query:    func(jq *JSONQ) *JSONQ { return jq.Distinct("price").Select("id", "price") },
+			expected: `[{"id":1,"price":1350},{"id":2,"price":1700},{"id":3,"price":1200},{"id":4,"price":850},{"id":6,"price":950}]`,
+		},
+		{
+			tag:      "offset and limit",
+			query:    func(jq *JSONQ) *JSONQ { return jq.WhereNotNil("id").Offset(2).Limit(2).Select("id") },
+			expected: `[{"id":3},{"id":4}]`,
+		},
+		{
+			tag:      "sorted",
+			query:    func(jq *JSONQ) *JSONQ { return jq.Where("price", "<", 1000).SortBy("id", "desc").Select("id") },
+			expected: `[{"id":6},{"id":5},{"id":4},{"id":null}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		jq := tc.query(New().FromString(jsonStr).From("vendor.items"))
+		out := iterate(t, jq)
+		assertJSON(t, out, tc.expected, tc.tag)
+
+		// the iterator gives the same result as Get
+		jq = tc.query(New().FromString(jsonStr).From("vendor.items"))
+		assertInterface(t, jq.Get(), out, tc.tag+" get")
+	}
+}
+
+// iterate collects the values of the iterator of jq
+func iterate(t *testing.T, jq *JSONQ) []interface{} {
+	out := make([]interface{}, 0)
+	it := jq.Iter()
+	for it.Next() {
+		out = append(out, it.Value().value)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	return out
+}
+
+// countingQueries returns a JSONQ instance over the items with the operator "counted" counting its evaluations
+func countingQueries(evaluated *int) *JSONQ {
+	jq := New().FromString(jsonStr)
+	jq.Macro("counted", func(x, y interface{}) (bool, error) {
+		*evaluated++
+		return eq(x, y)
+	})
+	return jq
+}
+
+func TestJSONQ_Iter_is_lazy(t *testing.T) {
+	var evaluated int
+	jq := countingQueries(&evaluated).From("vendor.items").Where("name", "counted", "Sony VAIO")
+	it := jq.Iter()
+	if !it.Next() || evaluated != 3 {
+		t.Errorf("expected 3 evaluations for the first match, got: %d", evaluated)
+	}
+	if it.Next() || evaluated != 7 {
+		t.Errorf("expected 7 evaluations at the end, got: %d", evaluated)
+	}
+	if it.Next() || it.Value().value != nil {
+		t.Error("expected exhausted iterator")
+	}
+
+	evaluated = 0
+	jq = countingQueries(&evaluated).From("vendor.items").Where("price", "counted", 850)
+	assertJSON(t, jq.First(), `{"id":4,"name":"Fujitsu","price":850}`, "first")
+	if evaluated != 4 {
+		t.Errorf("expected First to stop at the first match, got %d evaluations", evaluated)
+	}
+
+	evaluated = 0
+	jq = countingQueries(&evaluated).From("vendor.items").Where("price", "counted", 850).Limit(1)
+	assertJSON(t, jq.Get(), `[{"id":4,"name":"Fujitsu","price":850}]`, "limit")
+	if evaluated != 4 {
+		t.Errorf("expected Limit to stop at the first match, got %d evaluations", evaluated)
+	}
+}
+
+func TestJSONQ_Exists(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		jq       *JSONQ
+		expected bool
+	}{
+		{tag: "match", jq: New().FromString(jsonStr).From("vendor.items").WhereEqual("price", 850), expected: true},
+		{tag: "no match", jq: New().FromString(jsonStr).From("vendor.items").WhereEqual("price", 1), expected: false},
+		{tag: "object", jq: New().FromString(jsonStr).From("vendor"), expected: true},
+		{tag: "empty list", jq: New().FromString(`[]`), expected: false},
+	}
+	for _, tc := range testCases {
+		if tc.jq.Exists() != tc.expected {
+			t.Errorf("tag: %s, expected %v", tc.tag, tc.expected)
+		}
+	}
+}
+
+func TestJSONQ_Iter_errors(t *testing.T) {
+	testCases := []struct {
+		tag    string
+		jq     *JSONQ
+		target error
+	}{
+		{tag: "invalid operator", jq: New().FromString(jsonStr).From("vendor.items").Where("id", "invalid", 1), target: ErrInvalidOperator},
+		{tag: "too many rows", jq: New(WithMaxResultRows(2)).FromString(jsonStr).From("vendor.items"), target: ErrTooManyRows},
+	}
+	for _, tc := range testCases {
+		it := tc.jq.Iter()
+		for it.Next() {
+		}
+		if !errors.Is(it.Err(), tc.target) {
+			t.Errorf("tag: %s, expected %v got: %v", tc.tag, tc.target, it.Err())
+		}
+	}
+
+	it := New().FromString(jsonStr).From("vendor.items").Offset(-1).Iter()
+	if it.Next() || it.Err() == nil {
+		t.Error("failed to catch invalid offset")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	it = New().FromString(jsonStr).From("vendor.items").WithContext(ctx).Iter()
+	if it.Next() || !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("expected context canceled, got: %v", it.Err())
+	}
+}
+
+func TestJSONQ_Iter_with_index_and_trace(t *testing.T) {
+	jq := New(WithTracing()).FromString(jsonStr).CreateIndex("vendor.items", "price")
+	it := jq.From("vendor.items").WhereEqual("price", 850).Iter()
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if tr := jq.Trace(); n != 3 || tr.Rows != 3 || tr.Matched != 3 {
+		t.Errorf("expected 3 rows visited through the index, got: %d %+v", n, tr)
+	}
+}
+
+func Benchmark_First(b *testing.B) {
+	jq := New().FromString(benchmarkItems(10000))
+	for n := 0; n < b.N; n++ {
+		jq.Reset()
+		jq.From("items").WhereStartsWith("sku", "sku-1").First()
+	}
+}
@@ -0,0 +1,64 @@
+package gojsonq
+
+import "fmt"
+
+// Join embeds the rows of other whose foreignKey matches the localKey of a row as a list under the key as.
+// Rows without any match are dropped. The join is applied before the Where/OrWhere clauses, so the
+// embedded rows can be queried too. To join another node of the same document use a copy,
+// e.g: jq.From("users").Join(jq.Copy().From("orders"), "id", "user_id", "orders")
+func (j *JSONQ) Join(other *JSONQ, localKey, foreignKey, as string) *JSONQ {
+	return j.join(other, localKey, foreignKey, as, false)
+}
+
+// LeftJoin works like Join but keeps the rows without any match with an empty list under the key as
+func (j *JSONQ) LeftJoin(other *JSONQ, localKey, foreignKey, as string) *JSONQ {
+	return j.join(other, localKey, foreignKey, as, true)
+}
+
+// join builds a hash index of other using foreignKey and adds the join stage
+func (j *JSONQ) join(other *JSONQ, localKey, foreignKey, as string, keepUnmatched bool) *JSONQ {
+	v := other.Get()
+	j.errors = append(j.errors, other.Errors()...)
+	rows, ok := v.([]interface{})
+	if !ok {
+		return j.addError(fmt.Errorf("invalid join source type [%T]", v))
+	}
+
+	index := map[string][]interface{}{}
+	for _, r := range rows {
+		if _, ok := r.(map[string]interface{}); !ok {
+			continue
+		}
+		fv, err := getNestedValue(r, foreignKey, other.option.separator)
+		if err != nil {
+			continue
+		}
+		index[hashKey(fv)] = append(index[hashKey(fv)], r)
+	}
+
+	j.stages = append(j.stages, func(j *JSONQ, aa []interface{}) []interface{} {
+		result := make([]interface{}, 0, len(aa))
+		for _, a := range aa {
+			vm, ok := a.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var matched []interface{}
+			if lv, err := getNestedValue(vm, localKey, j.option.separator); err == nil {
+				matched = index[hashKey(lv)]
+			}
+			if len(matched) == 0 && !keepUnmatched {
+				continue
+			}
+			// copy the row, the original document must stay untouched
+			row := make(map[string]interface{}, len(vm)+1)
+			for k, v := range vm {
+				row[k] = v
+			}
+			row[as] = append(make([]interface{}, 0, len(matched)), matched...)
+			result = append(result, row)
+		}
+		return result
+	})
+	return j
+}
@@ -0,0 +1,60 @@
+package gojsonq
+
+import (
+	"testing"
+)
+
+const (
+	jsonStrJoinUsers  = `[{"id":1,"name":"John"},{"id":2,"name":"Ethan"},{"id":3,"name":"Tom"}]`
+	jsonStrJoinOrders = `{"orders":[{"id":10,"user_id":1,"total":20},{"id":11,"user_id":2,"total":15},{"id":12,"user_id":1,"total":5}]}`
+)
+
+func TestJSONQ_Join(t *testing.T) {
+	orders := New().FromString(jsonStrJoinOrders).From("orders")
+	jq := New().FromString(jsonStrJoinUsers).Join(orders, "id", "user_id", "orders")
+	expected := `[{"id":1,"name":"John","orders":[{"id":10,"total":20,"user_id":1},{"id":12,"total":5,"user_id":1}]},{"id":2,"name":"Ethan","orders":[{"id":11,"total":15,"user_id":2}]}]`
+	assertJSON(t, jq.Get(), expected, "inner join drops rows without match")
+}
+
+func TestJSONQ_LeftJoin(t *testing.T) {
+	orders := New().FromString(jsonStrJoinOrders).From("orders").Where("total", ">", 10)
+	jq := New().FromString(jsonStrJoinUsers).LeftJoin(orders, "id", "user_id", "orders")
+	expected := `[{"id":1,"name":"John","orders":[{"id":10,"total":20,"user_id":1}]},{"id":2,"name":"Ethan","orders":[{"id":11,"total":15,"user_id":2}]},{"id":3,"name":"Tom","orders":[]}]`
+	assertJSON(t, jq.Get(), expected, "left join keeps rows without match")
+}
+
+func TestJSONQ_Join_with_where_and_select(t *testing.T) {
+	orders := New().FromString(jsonStrJoinOrders).From("orders")
+	jq := New().FromString(jsonStrJoinUsers).
+		LeftJoin(orders, "id", "user_id", "orders").
+		WhereLenEqual("orders", 0).
+		Select("name")
+	assertJSON(t, jq.Get(), `[{"name":"Tom"}]`, "joined rows can be queried")
+}
+
+func TestJSONQ_Join_same_document(t *testing.T) {
+	jq := New().FromString(`{"users":` + jsonStrJoinUsers + `,` + jsonStrJoinOrders[1:])
+	out := jq.From("users").Join(jq.Copy().From("orders"), "id", "user_id", "orders").SortBy("id", "desc").Pluck("name")
+	assertJSON(t, out, `["Ethan","John"]`, "join with another node of the same document")
+	if err := jq.Error(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestJSONQ_Join_does_not_modify_source(t *testing.T) {
+	jq := New().FromString(jsonStrJoinUsers)
+	jq.Join(New().FromString(jsonStrJoinOrders).From("orders"), "id", "user_id", "orders").Get()
+	assertJSON(t, jq.Reset().Get(), jsonStrJoinUsers, "source document must stay untouched")
+}
+
+func TestJSONQ_Join_with_invalid_source(t *testing.T) {
+	jq := New().FromString(jsonStrJoinUsers).Join(New().FromString(`{"id":1}`), "id", "id", "x")
+	if jq.Error() == nil {
+		t.Error("failed to catch invalid join source")
+	}
+
+	jq = New().FromString(jsonStrJoinUsers).Join(New().FromString(jsonStrJoinOrders).From("invalid"), "id", "id", "x")
+	if jq.Error() == nil {
+		t.Error("failed to propagate join source errors")
+	}
+}
@@ -1,11 +1,14 @@
 package gojsonq
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"time"
 )
 
 // New returns a new instance of JSONQ
@@ -13,8 +16,10 @@ func New(options ...OptionFunc) *JSONQ {
 	jq := &JSONQ{
 		queryMap: defaultQueries(),
 		option: option{
-			decoder:   &DefaultDecoder{},
-			separator: defaultSeparator,
+			decoder:       &DefaultDecoder{},
+			encoder:       &DefaultEncoder{},
+			separator:     defaultSeparator,
+			decompression: true,
 		},
 	}
 	for _, option := range options {
@@ -50,9 +55,23 @@ type JSONQ struct {
 	offsetRecords    int                  // number of records that will be skipped in final result
 	limitRecords     int                  // number of records that will be available in final result
 	distinctProperty string               // contain the distinct attribute name
+	stages           []stage              // transformations applied to the list before the queries
+	selectColumns    []column             // pre-built select columns, e.g: SelectFunc
+	predicates       [][]predicate        // pre-compiled queries, e.g: PreparedQuery
+	filtered         bool                 // the queries are already applied to jsonContent, e.g: by SortBy
+	projected        bool                 // the columns are already selected in jsonContent
+	indexes          []*index             // secondary indexes, see CreateIndex
+	origins          map[string]int       // source of the merged values by path, see Merge
+	sortPlan         *SortPlan            // last sort applied, reported by Explain
+	trace            *Trace               // statistics of the where clauses, see WithTracing
+	ctx              context.Context      // context checked while filtering, sorting and grouping
+	ctxErr           error                // error of the context once reported
 	errors           []error              // contains all the errors when processing
 }
 
+// stage describes a transformation of a list, e.g: join
+type stage func(j *JSONQ, aa []interface{}) []interface{}
+
 // String satisfies stringer interface
 func (j *JSONQ) String() string {
 	return fmt.Sprintf("\nContent: %s\nQueries:%v\n", string(j.raw), j.queries)
@@ -60,9 +79,12 @@ func (j *JSONQ) String() string {
 
 // decode decodes the raw message to Go data structure
 func (j *JSONQ) decode() *JSONQ {
+	if err := j.checkLimits(j.raw); err != nil {
+		return j.addError(err)
+	}
 	err := j.option.decoder.Decode(j.raw, &j.rootJSONContent)
 	if err != nil {
-		return j.addError(err)
+		return j.addError(newDecodeError(j.raw, err))
 	}
 	j.jsonContent = j.rootJSONContent
 	return j
@@ -72,17 +94,21 @@ func (j *JSONQ) decode() *JSONQ {
 // concurrent operation on the same data without being decoded again
 func (j *JSONQ) Copy() *JSONQ {
 	tmp := *j
+	tmp.queryMap = make(map[string]QueryFunc, len(j.queryMap))
+	for op, fn := range j.queryMap {
+		tmp.queryMap[op] = fn
+	}
 	return tmp.reset()
 }
 
 // File read the json content from physical file
 func (j *JSONQ) File(filename string) *JSONQ {
-	bb, err := ioutil.ReadFile(filename)
+	f, err := os.Open(filename)
 	if err != nil {
 		return j.addError(err)
 	}
-	j.raw = bb
-	return j.decode() // handle error
+	defer f.Close()
+	return j.Reader(f)
 }
 
 // JSONString reads the json content from valid json string
@@ -97,15 +123,17 @@ func (j *JSONQ) FromString(str string) *JSONQ {
 	return j.decode() // handle error
 }
 
-// Reader reads the json content from io reader
+// Reader reads the json content from io reader, gzip, zlib and bzip2 content is decompressed. See WithDecompression
 func (j *JSONQ) Reader(r io.Reader) *JSONQ {
-	buf := new(bytes.Buffer)
-	_, err := buf.ReadFrom(r)
+	r, err := j.decompress(r)
 	if err != nil {
 		return j.addError(err)
 	}
-	j.raw = buf.Bytes()
-	buf.Reset() // reset the buffer
+	bb, err := j.readAll(r)
+	if err != nil {
+		return j.addError(err)
+	}
+	j.raw = bb
 	return j.decode()
 }
 
@@ -125,14 +153,14 @@ func (j *JSONQ) Errors() []error {
 
 // addError adds error to error list
 func (j *JSONQ) addError(err error) *JSONQ {
-	j.errors = append(j.errors, fmt.Errorf("gojsonq: %v", err))
+	j.errors = append(j.errors, fmt.Errorf("gojsonq: %w", err))
 	return j
 }
 
 // Macro adds a new query func to the JSONQ
 func (j *JSONQ) Macro(operator string, fn QueryFunc) *JSONQ {
 	if _, ok := j.queryMap[operator]; ok {
-		j.addError(fmt.Errorf("%s is already registered in query map", operator))
+		j.addError(&OperatorError{Operator: operator, Err: ErrOperatorExists})
 		return j
 	}
 	j.queryMap[operator] = fn
@@ -142,6 +170,7 @@ func (j *JSONQ) Macro(operator string, fn QueryFunc) *JSONQ {
 // From seeks the json content to provided node. e.g: "users.[0]"  or "users.[0].name"
 func (j *JSONQ) From(node string) *JSONQ {
 	j.node = node
+	j.filtered, j.projected = false, false
 	v, err := getNestedValue(j.jsonContent, node, j.option.separator)
 	if err != nil {
 		j.addError(err)
@@ -162,12 +191,22 @@ func (j *JSONQ) FromInterface(v interface{}) *JSONQ {
 	return j
 }
 
-// Select use for selection of the properties from query result
+// Select use for selection of the properties from query result.
+// Besides node names, a property can be an expression computed for each row using
+// arithmetic/comparison operators and built-in functions e.g: Select("price * qty as total", "upper(name) as NAME")
 func (j *JSONQ) Select(properties ...string) *JSONQ {
+	j.projected = false
 	j.attributes = append(j.attributes, properties...)
 	return j
 }
 
+// SelectFunc adds a column to the query result whose value is computed by fn for each row
+func (j *JSONQ) SelectFunc(alias string, fn func(row *Result) interface{}) *JSONQ {
+	j.projected = false
+	j.selectColumns = append(j.selectColumns, column{alias: alias, fn: fn})
+	return j
+}
+
 // Offset skips the number of records in result
 func (j *JSONQ) Offset(offset int) *JSONQ {
 	j.offsetRecords = offset
@@ -217,6 +256,7 @@ func (j *JSONQ) Where(key, cond string, val interface{}) *JSONQ {
 		operator: cond,
 		value:    val,
 	}
+	j.filtered = false
 	if j.queryIndex == 0 && len(j.queries) == 0 {
 		var qq []query
 		qq = append(qq, q)
@@ -262,6 +302,7 @@ func (j *JSONQ) WhereNotIn(key string, val interface{}) *JSONQ {
 
 // OrWhere builds an OrWhere clause, basically it's a group of AND clauses
 func (j *JSONQ) OrWhere(key, cond string, val interface{}) *JSONQ {
+	j.filtered = false
 	j.queryIndex++
 	var qq []query
 	qq = append(qq, query{
@@ -305,69 +346,165 @@ func (j *JSONQ) WhereLenNotEqual(key string, val interface{}) *JSONQ {
 }
 
 // findInArray traverses through a list and returns the value list.
+// Only the rows at positions are visited when positions is not nil, e.g: the candidates found by an index.
 // This helps to process Where/OrWhere queries
-func (j *JSONQ) findInArray(aa []interface{}) []interface{} {
+func (j *JSONQ) findInArray(aa []interface{}, positions []int, groups [][]predicate) []interface{} {
+	if positions == nil {
+		positions = make([]int, len(aa))
+		for i := range positions {
+			positions[i] = i
+		}
+	}
+	if j.option.tracing {
+		defer j.traceScan(len(positions), groups, time.Now())()
+	}
+	var result []interface{}
+	if j.option.parallelism > 1 && len(positions) >= parallelMinRows {
+		result = j.scanParallel(aa, positions, groups)
+	} else {
+		result = j.scan(aa, positions, groups)
+	}
+	if j.trace != nil {
+		j.trace.Matched += len(result)
+	}
+	return result
+}
+
+// scan returns the rows at positions matching the groups
+func (j *JSONQ) scan(aa []interface{}, positions []int, groups [][]predicate) []interface{} {
 	result := make([]interface{}, 0)
-	for _, a := range aa {
-		if m, ok := a.(map[string]interface{}); ok {
-			result = append(result, j.findInMap(m)...)
+	for k, i := range positions {
+		if k%cancelCheckInterval == 0 && j.canceled() {
+			return make([]interface{}, 0)
+		}
+		if m, ok := aa[i].(map[string]interface{}); ok && j.matches(i, m, groups) {
+			result = append(result, m)
+		}
+		// one more row than the maximum is enough to report the limit
+		if max := j.option.maxResultRows; max > 0 && len(result) > max {
+			break
 		}
 	}
 	return result
 }
 
-// findInMap traverses through a map and returns the matched value list.
+// matches checks whether the map at position row of the list satisfies the groups.
 // This helps to process Where/OrWhere queries
-func (j *JSONQ) findInMap(vm map[string]interface{}) []interface{} {
-	result := make([]interface{}, 0)
+func (j *JSONQ) matches(row int, vm map[string]interface{}, groups [][]predicate) bool {
 	orPassed := false
-	for _, qList := range j.queries {
+	for gi, group := range groups {
 		andPassed := true
-		for _, q := range qList {
-			cf, ok := j.queryMap[q.operator]
-			if !ok {
-				j.addError(fmt.Errorf("invalid operator %s", q.operator))
-				return result
+		for pi, p := range group {
+			var start time.Time
+			if j.trace != nil {
+				start = time.Now()
 			}
-			nv, errnv := getNestedValue(vm, q.key, j.option.separator)
+			nv, errnv := getNestedValueParts(vm, p.path)
 			if errnv != nil {
-				j.addError(errnv)
-				andPassed = false
-			} else {
-				qb, err := cf(nv, q.value)
-				if err != nil {
-					j.addError(err)
+				var ok bool
+				nv, ok = j.rowError(&PredicateError{Row: row, Key: p.key, Operator: p.operator, Err: withPath(errnv, p.key)})
+				if !ok {
+					andPassed = false
+					j.tracePredicate(gi, pi, false, errnv, start)
+					continue
 				}
-				andPassed = andPassed && qb
 			}
+			qb, err := p.fn(nv, p.value)
+			if err != nil {
+				j.rowError(&PredicateError{Row: row, Key: p.key, Operator: p.operator, Err: err})
+			}
+			andPassed = andPassed && qb
+			j.tracePredicate(gi, pi, qb, err, start)
 		}
 		orPassed = orPassed || andPassed
 	}
-	if orPassed {
-		result = append(result, vm)
+	return orPassed
+}
+
+// predicate describes a query with its resolved query function and split key
+type predicate struct {
+	query
+	path []string
+	fn   QueryFunc
+}
+
+// compileQueries resolves the query functions and splits the keys of the queries once,
+// so that they are not looked up again for every row
+func (j *JSONQ) compileQueries() ([][]predicate, error) {
+	groups := make([][]predicate, 0, len(j.queries))
+	for _, qList := range j.queries {
+		group := make([]predicate, 0, len(qList))
+		for _, q := range qList {
+			fn, ok := j.queryMap[q.operator]
+			if !ok {
+				return nil, &OperatorError{Operator: q.operator, Err: ErrInvalidOperator}
+			}
+			group = append(group, predicate{query: q, path: strings.Split(q.key, j.option.separator), fn: fn})
+		}
+		groups = append(groups, group)
 	}
-	return result
+	return groups, nil
+}
+
+// processStages applies the pending stages to the list. Stages are consumed once applied
+func (j *JSONQ) processStages() *JSONQ {
+	if aa, ok := j.jsonContent.([]interface{}); ok {
+		for _, s := range j.stages {
+			aa = s(j, aa)
+		}
+		j.jsonContent = aa
+	}
+	j.stages = nil
+	return j
 }
 
 // processQuery makes the result
 func (j *JSONQ) processQuery() *JSONQ {
 	if aa, ok := j.jsonContent.([]interface{}); ok {
-		j.jsonContent = j.findInArray(aa)
+		groups := j.predicates
+		if groups == nil {
+			var err error
+			if groups, err = j.compileQueries(); err != nil {
+				j.addError(err)
+				j.jsonContent = make([]interface{}, 0)
+				return j
+			}
+		}
+		var positions []int
+		if idx, p := j.findIndex(aa, groups); idx != nil {
+			positions = j.candidates(idx, p)
+		}
+		j.jsonContent = j.findInArray(aa, positions, groups)
 	}
 	return j
 }
 
 // prepare builds the queries
 func (j *JSONQ) prepare() *JSONQ {
-	if len(j.queries) > 0 {
+	j.filter()
+	// the columns are selected once, after sorting, e.g: SortBy followed by Get
+	if !j.projected && (len(j.attributes) > 0 || len(j.selectColumns) > 0) {
+		j.jsonContent = j.project(append(j.columns(j.attributes), j.selectColumns...))
+		j.projected = true
+	}
+	j.capRows()
+	return j
+}
+
+// filter applies the stages, the queries and Distinct without selecting the columns
+func (j *JSONQ) filter() *JSONQ {
+	if len(j.stages) > 0 {
+		j.processStages()
+		j.filtered = false
+	}
+	// the queries are applied once, e.g: SortBy followed by Get does not filter the rows again
+	if !j.filtered && (len(j.queries) > 0 || len(j.predicates) > 0) {
 		j.processQuery()
+		j.filtered = true
 	}
 	if j.distinctProperty != "" {
 		j.distinct()
 	}
-	if len(j.attributes) > 0 {
-		j.jsonContent = j.only(j.attributes...)
-	}
 	j.queryIndex = 0
 	return j
 }
@@ -378,12 +515,13 @@ func (j *JSONQ) GroupBy(property string) *JSONQ {
 
 	dt := map[string][]interface{}{}
 	if aa, ok := j.jsonContent.([]interface{}); ok {
-		for _, a := range aa {
+		for i, a := range aa {
+			if i%cancelCheckInterval == 0 && j.canceled() {
+				dt = map[string][]interface{}{}
+				break
+			}
 			if vm, ok := a.(map[string]interface{}); ok {
-				v, err := getNestedValue(vm, property, j.option.separator)
-				if err != nil {
-					j.addError(err)
-				} else {
+				if v, ok := j.lookup(vm, property); ok {
 					dt[toString(v)] = append(dt[toString(v)], vm)
 				}
 			}
@@ -406,29 +544,36 @@ func (j *JSONQ) Sort(order ...string) *JSONQ {
 	if len(order) > 0 && order[0] == "desc" {
 		asc = false
 	}
-	if arr, ok := j.jsonContent.([]interface{}); ok {
+	if arr, ok := j.jsonContent.([]interface{}); ok && !j.canceled() {
 		j.jsonContent = sortList(arr, asc)
 	}
+	j.sortPlan = &SortPlan{Ascending: asc}
 	return j
 }
 
 // SortBy sorts an array
-// default ascending order, pass "desc" for descending order
+// default ascending order, pass "desc" for descending order. Null values come first in ascending order,
+// the rows missing the property are kept unless the missing key policy is MissingKeyIgnore
 func (j *JSONQ) SortBy(order ...string) *JSONQ {
-	j.prepare()
-	asc := true
+	j.filter()
+	j.capRows()
+	property, asc, err := sortByOrder(order)
+	if err != nil {
+		return j.addError(err)
+	}
+	j.sortPlan = &SortPlan{Property: property, Ascending: asc}
+	return j.sortBy(property, asc)
+}
+
+// sortByOrder validates the arguments of SortBy and returns the property name and the order
+func sortByOrder(order []string) (string, bool, error) {
 	if len(order) == 0 {
-		return j.addError(fmt.Errorf("provide at least one argument as property name"))
+		return "", false, fmt.Errorf("provide at least one argument as property name")
 	}
 	if len(order) > 2 {
-		return j.addError(fmt.Errorf("sort accepts only two arguments. first argument property name and second argument asc/desc"))
+		return "", false, fmt.Errorf("sort accepts only two arguments. first argument property name and second argument asc/desc")
 	}
-
-	if len(order) > 1 && order[1] == "desc" {
-		asc = false
-	}
-
-	return j.sortBy(order[0], asc)
+	return order[0], len(order) < 2 || order[1] != "desc", nil
 }
 
 // Distinct builds distinct value using provided attribute/column/property
@@ -444,10 +589,7 @@ func (j *JSONQ) distinct() *JSONQ {
 	if aa, ok := j.jsonContent.([]interface{}); ok {
 		for _, a := range aa {
 			if vm, ok := a.(map[string]interface{}); ok {
-				v, err := getNestedValue(vm, j.distinctProperty, j.option.separator)
-				if err != nil {
-					j.addError(err)
-				} else {
+				if v, ok := j.lookup(vm, j.distinctProperty); ok {
 					if _, exist := m[toString(v)]; !exist {
 						dt = append(dt, vm)
 						m[toString(v)] = true
@@ -463,24 +605,46 @@ func (j *JSONQ) distinct() *JSONQ {
 
 // sortBy sorts list of map
 func (j *JSONQ) sortBy(property string, asc bool) *JSONQ {
-	sortResult, ok := j.jsonContent.([]interface{})
+	list, ok := j.jsonContent.([]interface{})
 	if !ok {
 		return j
 	}
-	if len(sortResult) == 0 {
+	if len(list) == 0 {
 		return j
 	}
+	// sort a copy, the list may be shared with the original document
+	sortResult := make([]interface{}, 0, len(list))
+
+	// report the missing keys and the values which can not be compared once per row,
+	// the rows missing the key are skipped when the policy ignores them
+	for _, a := range list {
+		if _, ok := a.(map[string]interface{}); !ok {
+			sortResult = append(sortResult, a)
+			continue
+		}
+		v, ok := j.lookup(a, property)
+		if !ok && j.option.missingKey == MissingKeyIgnore {
+			continue
+		}
+		switch v.(type) {
+		case nil, float64, string:
+		default:
+			j.rowError(&TypeError{Value: v, Expected: "numeric or string"})
+		}
+		sortResult = append(sortResult, a)
+	}
 
 	sm := &sortMap{}
+	sm.canceled = j.canceled
 	sm.separator = j.option.separator
 	sm.key = property
 	if !asc {
 		sm.desc = true
 	}
 	sm.Sort(sortResult)
-
-	for _, e := range sm.errs {
-		j.addError(e)
+	if j.canceled() {
+		j.jsonContent = make([]interface{}, 0)
+		return j
 	}
 
 	// replace the new result with the previous result
@@ -490,20 +654,24 @@ func (j *JSONQ) sortBy(property string, asc bool) *JSONQ {
 
 // only return selected properties in result
 func (j *JSONQ) only(properties ...string) interface{} {
+	return j.project(j.columns(properties))
+}
+
+// columns builds the columns of the selected properties
+func (j *JSONQ) columns(properties []string) []column {
+	cc := make([]column, 0, len(properties))
+	for _, prop := range properties {
+		cc = append(cc, makeColumn(prop, j.option.separator))
+	}
+	return cc
+}
+
+// project builds a new object with the columns for each object of the list
+func (j *JSONQ) project(columns []column) interface{} {
 	var result = make([]interface{}, 0)
 	if aa, ok := j.jsonContent.([]interface{}); ok {
 		for _, am := range aa {
-			tmap := map[string]interface{}{}
-			for _, prop := range properties {
-				node, alias := makeAlias(prop, j.option.separator)
-				rv, errV := getNestedValue(am, node, j.option.separator)
-				if errV != nil {
-					j.addError(errV)
-					continue
-				}
-				tmap[alias] = rv
-			}
-			if len(tmap) > 0 {
+			if tmap, ok := j.projectRow(am, columns); ok {
 				result = append(result, tmap)
 			}
 		}
@@ -511,6 +679,22 @@ func (j *JSONQ) only(properties ...string) interface{} {
 	return result
 }
 
+// projectRow builds a new object with the columns of the row, ok is false when no column is available
+func (j *JSONQ) projectRow(am interface{}, columns []column) (map[string]interface{}, bool) {
+	tmap := map[string]interface{}{}
+	for _, c := range columns {
+		rv, errV := c.value(am)
+		if errV != nil {
+			var ok bool
+			if rv, ok = j.rowError(withPath(errV, c.node)); !ok {
+				continue
+			}
+		}
+		tmap[c.alias] = rv
+	}
+	return tmap, len(tmap) > 0
+}
+
 // Only collects the properties from a list of object
 func (j *JSONQ) Only(properties ...string) interface{} {
 	return j.prepare().only(properties...)
@@ -522,7 +706,7 @@ func (j *JSONQ) OnlyR(properties ...string) (*Result, error) {
 	if err := j.Error(); err != nil {
 		return nil, err
 	}
-	return NewResult(v), nil
+	return j.result(v), nil
 }
 
 // Pluck build an array of values form a property of a list of objects
@@ -553,7 +737,7 @@ func (j *JSONQ) PluckR(property string) (*Result, error) {
 	if err := j.Error(); err != nil {
 		return nil, err
 	}
-	return NewResult(v), nil
+	return j.result(v), nil
 }
 
 // reset resets the current state of JSONQ instance
@@ -567,6 +751,13 @@ func (j *JSONQ) reset() *JSONQ {
 	j.offsetRecords = 0
 	j.limitRecords = 0
 	j.distinctProperty = ""
+	j.stages = nil
+	j.selectColumns = nil
+	j.predicates = nil
+	j.filtered, j.projected = false, false
+	j.sortPlan = nil
+	j.trace = nil
+	j.ctxErr = nil
 	j.errors = make([]error, 0)
 	return j
 }
@@ -578,6 +769,17 @@ func (j *JSONQ) Reset() *JSONQ {
 
 // Get return the result
 func (j *JSONQ) Get() interface{} {
+	// with a limit the rows after the limit are never evaluated
+	if j.limitRecords > 0 {
+		if it := j.Iter(); !it.single {
+			result := make([]interface{}, 0, j.limitRecords)
+			for it.Next() {
+				result = append(result, it.value)
+			}
+			j.jsonContent, j.filtered, j.projected = result, true, true
+			return result
+		}
+	}
 	j.prepare()
 	if j.offsetRecords != 0 {
 		j.offset()
@@ -594,16 +796,13 @@ func (j *JSONQ) GetR() (*Result, error) {
 	if err := j.Error(); err != nil {
 		return nil, err
 	}
-	return NewResult(v), nil
+	return j.result(v), nil
 }
 
-// First returns the first element of a list
+// First returns the first element of a list, the rows after the first match are never evaluated
 func (j *JSONQ) First() interface{} {
-	j.prepare()
-	if arr, ok := j.jsonContent.([]interface{}); ok {
-		if len(arr) > 0 {
-			return arr[0]
-		}
+	if it := j.iterator(0, 0); !it.single && it.Next() {
+		return it.value
 	}
 	return empty
 }
@@ -614,7 +813,7 @@ func (j *JSONQ) FirstR() (*Result, error) {
 	if err := j.Error(); err != nil {
 		return nil, err
 	}
-	return NewResult(v), nil
+	return j.result(v), nil
 }
 
 // Last returns the last element of a list
@@ -634,7 +833,7 @@ func (j *JSONQ) LastR() (*Result, error) {
 	if err := j.Error(); err != nil {
 		return nil, err
 	}
-	return NewResult(v), nil
+	return j.result(v), nil
 }
 
 // Nth returns the nth element of a list
@@ -648,11 +847,11 @@ func (j *JSONQ) Nth(index int) interface{} {
 	if arr, ok := j.jsonContent.([]interface{}); ok {
 		alen := len(arr)
 		if alen == 0 {
-			j.addError(fmt.Errorf("list is empty"))
+			j.addError(ErrEmptyList)
 			return empty
 		}
 		if abs(index) > alen {
-			j.addError(fmt.Errorf("index out of range"))
+			j.addError(ErrIndexOutOfRange)
 			return empty
 		}
 		if index > 0 {
@@ -669,7 +868,7 @@ func (j *JSONQ) NthR(index int) (*Result, error) {
 	if err := j.Error(); err != nil {
 		return nil, err
 	}
-	return NewResult(v), nil
+	return j.result(v), nil
 }
 
 // Find returns the result of a exact matching path
@@ -683,7 +882,7 @@ func (j *JSONQ) FindR(path string) (*Result, error) {
 	if err := j.Error(); err != nil {
 		return nil, err
 	}
-	return NewResult(v), nil
+	return j.result(v), nil
 }
 
 // Count returns the number of total items.
@@ -707,21 +906,32 @@ func (j *JSONQ) Count() int {
 	return lnth
 }
 
-// Out write the queried data to defined custom type
+// Out write the queried data to defined custom type, v must be a non-nil pointer.
+// The data is assigned directly following the json struct tags, a failure is reported
+// with the path of the value, e.g: users[3].age: cannot assign string to int
 func (j *JSONQ) Out(v interface{}) {
-	data, err := json.Marshal(j.Get())
-	if err != nil {
-		j.addError(err)
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		j.addError(&TypeError{Value: v, Expected: "a non-nil pointer"})
 		return
 	}
-	if err := json.Unmarshal(data, &v); err != nil {
+	if err := convert(j.Get(), rv.Elem()); err != nil {
 		j.addError(err)
 	}
 }
 
-// Writer write the queried data to a io.Writer
+// Writer write the queried data to a io.Writer using the encoder, default single line JSON. See WithEncoder
 func (j *JSONQ) Writer(w io.Writer) {
-	err := json.NewEncoder(w).Encode(j.Get())
+	var err error
+	if ce, ok := j.option.encoder.(ColumnEncoder); ok {
+		var columns []string
+		for _, c := range append(j.columns(j.attributes), j.selectColumns...) {
+			columns = append(columns, c.alias)
+		}
+		err = ce.EncodeColumns(w, j.Get(), columns)
+	} else {
+		err = j.option.encoder.Encode(w, j.Get())
+	}
 	if err != nil {
 		j.addError(err)
 		return
@@ -738,6 +948,10 @@ func (j *JSONQ) More() *JSONQ {
 	j.queryIndex = 0
 	j.limitRecords = 0
 	j.distinctProperty = ""
+	j.stages = nil
+	j.selectColumns = nil
+	j.predicates = nil
+	j.filtered, j.projected = false, false
 	return j
 }
 
@@ -757,23 +971,45 @@ func (j *JSONQ) getFloatValFromArray(arr []interface{}, property ...string) []fl
 				j.addError(fmt.Errorf("property name can not be empty for object"))
 				return nil
 			}
-			if fi, ok := mv[property[0]]; ok {
-				if flt, ok := fi.(float64); ok {
-					ff = append(ff, flt)
-				} else {
-					j.addError(fmt.Errorf("property %s's value '%v' is not numeric", property[0], fi))
-					return nil
-				}
-			} else {
-				j.addError(fmt.Errorf("property '%s' does not exist", property[0]))
+			flt, ok, err := j.numericValue(mv, property[0])
+			if err != nil {
+				j.addError(err)
 				return nil
 			}
+			if ok {
+				ff = append(ff, flt)
+			}
 		}
 	}
 
 	return ff
 }
 
+// numericValue returns the value of the property of the object for aggregation applying the policies.
+// ok is false when the value is skipped, like null values (also the missing keys treated as null) are
+// skipped by SQL aggregations, err is not nil when the aggregation fails
+func (j *JSONQ) numericValue(mv map[string]interface{}, property string) (f float64, ok bool, err error) {
+	fi, exists := mv[property]
+	if !exists {
+		switch j.option.missingKey {
+		case MissingKeyIgnore:
+			return 0, false, nil
+		case MissingKeyError:
+			return 0, false, &PathError{Path: property, Segment: property, Err: ErrInvalidNode}
+		}
+	}
+	if fi == nil {
+		return 0, false, nil
+	}
+	if f, ok = fi.(float64); !ok {
+		if j.option.typeMismatch == TypeMismatchIgnore {
+			return 0, false, nil
+		}
+		return 0, false, &TypeError{Value: fi, Expected: "numeric"}
+	}
+	return f, true, nil
+}
+
 // getAggregationValues returns a list of float64 values for aggregation
 func (j *JSONQ) getAggregationValues(property ...string) []float64 {
 	j.prepare()
@@ -794,17 +1030,14 @@ func (j *JSONQ) getAggregationValues(property ...string) []float64 {
 			j.addError(fmt.Errorf("property can not be empty for object"))
 			return nil
 		}
-		if fi, ok := mv[property[0]]; ok {
-			if flt, ok := fi.(float64); ok {
-				ff = append(ff, flt)
-			} else {
-				j.addError(fmt.Errorf("property %s's value '%v' is not numeric", property[0], fi))
-				return nil
-			}
-		} else {
-			j.addError(fmt.Errorf("property '%s' does not exist", property[0]))
+		flt, ok, err := j.numericValue(mv, property[0])
+		if err != nil {
+			j.addError(err)
 			return nil
 		}
+		if ok {
+			ff = append(ff, flt)
+		}
 	}
 	return ff
 }
@@ -243,6 +243,75 @@ func TestJSONQ_Select(t *testing.T) {
 	}
 }
 
+func TestJSONQ_Select_computed_columns(t *testing.T) {
+	jq := New().FromString(jsonStr).
+		From("vendor.items").
+		WhereIn("id", []int{1, 7, 6}).
+		Select("id", "price * 2 as double", "upper(name) AS NAME", "coalesce(key, id, 0) as display")
+	expected := `[{"NAME":"MACBOOK PRO 13 INCH RETINA","display":1,"double":2700,"id":1},{"NAME":"HP CORE I7","display":6,"double":1900,"id":6}]`
+	assertJSON(t, jq.Get(), expected, "select computed columns")
+
+	jq = New().FromString(jsonStr).From("vendor.items").Where("id", "=", 5).Select("coalesce(key, id) as display", "price - key")
+	assertJSON(t, jq.Get(), `[{"display":2300,"price - key":-1450}]`, "select computed columns without alias")
+}
+
+func TestJSONQ_Select_computed_columns_error(t *testing.T) {
+	jq := New().FromString(jsonStr).From("vendor.items").Select("name", "name * 2 as x")
+	jq.Get()
+	if jq.Error() == nil {
+		t.Error("failed to catch invalid operands")
+	}
+}
+
+func TestJSONQ_Select_node_named_like_an_expression(t *testing.T) {
+	jq := New().FromString(`[{"a/b":1,"a":6,"b":2},{"a":6,"b":3}]`).Select("a/b")
+	assertJSON(t, jq.Get(), `[{"a/b":1},{"a/b":2}]`, "select node named like an expression")
+}
+
+func TestJSONQ_Select_computed_columns_missing_path(t *testing.T) {
+	jq := New().FromString(jsonStr).From("vendor.items").WhereIn("id", []int{1, 2}).
+		Select("id", "price * 2 as double").SortBy("id")
+	assertJSON(t, jq.Get(), `[{"double":2700,"id":1},{"double":3400,"id":2}]`, "select computed column sorted")
+	if jq.Error() != nil {
+		t.Errorf("unexpected error: %v", jq.Error())
+	}
+
+	jq = New().FromString(jsonStr).From("vendor.items").Where("id", "=", 1).Select("id", "key * 2 as double")
+	assertJSON(t, jq.Get(), `[{"id":1}]`, "select computed column with missing path")
+	if jq.Error() == nil {
+		t.Error("failed to report the missing path of a computed column")
+	}
+
+	jq = New(WithMissingKeyPolicy(MissingKeyTreatAsNull)).FromString(jsonStr).From("vendor.items").
+		Where("id", "=", 1).Select("id", "key * 2 as double")
+	assertJSON(t, jq.Get(), `[{"double":null,"id":1}]`, "select computed column with missing path as null")
+	if jq.Error() != nil {
+		t.Errorf("unexpected error: %v", jq.Error())
+	}
+}
+
+func TestJSONQ_Select_sorted_by_unselected_property(t *testing.T) {
+	jq := New().FromString(jsonStr).From("vendor.items").Where("price", ">", 1000).
+		Select("name").SortBy("price", "desc").Limit(2)
+	assertJSON(t, jq.Get(), `[{"name":"MacBook Pro 15 inch retina"},{"name":"MacBook Pro 13 inch retina"}]`, "select sorted by unselected property")
+	assertJSON(t, jq.Get(), `[{"name":"MacBook Pro 15 inch retina"},{"name":"MacBook Pro 13 inch retina"}]`, "select sorted by unselected property again")
+	if jq.Error() != nil {
+		t.Errorf("unexpected error: %v", jq.Error())
+	}
+}
+
+func TestJSONQ_SelectFunc(t *testing.T) {
+	jq := New().FromString(jsonStr).
+		From("vendor.items").
+		WhereIn("id", []int{1, 2}).
+		Select("id").
+		SelectFunc("expensive", func(row *Result) interface{} {
+			price, _ := NewResult(row.value.(map[string]interface{})["price"]).Float64()
+			return price > 1500
+		})
+	assertJSON(t, jq.Get(), `[{"expensive":false,"id":1},{"expensive":true,"id":2}]`, "select func")
+}
+
 func TestJSONQ_Offset(t *testing.T) {
 	jq := New().Offset(3)
 	if jq.offsetRecords != 3 {
@@ -0,0 +1,157 @@
+package gojsonq
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// cancelCheckInterval is the number of rows or comparisons between two checks of the context
+const cancelCheckInterval = 64
+
+// WithContext sets the context checked while filtering, sorting and grouping by the following
+// operations, they stop early and report the error of the context once it is done
+func (j *JSONQ) WithContext(ctx context.Context) *JSONQ {
+	j.ctx = ctx
+	return j
+}
+
+// canceled checks whether the context is done, the error of the context is reported once
+func (j *JSONQ) canceled() bool {
+	if j.ctx == nil {
+		return false
+	}
+	err := j.ctx.Err()
+	if err != nil && j.ctxErr == nil {
+		j.ctxErr = err
+		j.addError(err)
+	}
+	return err != nil
+}
+
+// withContext sets the context for the duration of a call, the returned func restores the previous context
+func (j *JSONQ) withContext(ctx context.Context) func() {
+	prev := j.ctx
+	j.ctx, j.ctxErr = ctx, nil
+	j.canceled()
+	return func() {
+		j.ctx = prev
+	}
+}
+
+// contextError returns the error of the context if it stopped the call, otherwise the first occurred error
+func (j *JSONQ) contextError() error {
+	if j.ctxErr != nil {
+		return fmt.Errorf("gojsonq: %w", j.ctxErr)
+	}
+	return j.Error()
+}
+
+// GetContext returns the result, it stops early when the context is done
+func (j *JSONQ) GetContext(ctx context.Context) (interface{}, error) {
+	defer j.withContext(ctx)()
+	v := j.Get()
+	return v, j.contextError()
+}
+
+// FirstContext returns the first element of a list, it stops early when the context is done
+func (j *JSONQ) FirstContext(ctx context.Context) (interface{}, error) {
+	defer j.withContext(ctx)()
+	v := j.First()
+	return v, j.contextError()
+}
+
+// LastContext returns the last element of a list, it stops early when the context is done
+func (j *JSONQ) LastContext(ctx context.Context) (interface{}, error) {
+	defer j.withContext(ctx)()
+	v := j.Last()
+	return v, j.contextError()
+}
+
+// FindContext returns the result of a exact matching path, it stops early when the context is done
+func (j *JSONQ) FindContext(ctx context.Context, path string) (interface{}, error) {
+	defer j.withContext(ctx)()
+	v := j.Find(path)
+	return v, j.contextError()
+}
+
+// CountContext returns the number of total items, it stops early when the context is done
+func (j *JSONQ) CountContext(ctx context.Context) (int, error) {
+	defer j.withContext(ctx)()
+	c := j.Count()
+	return c, j.contextError()
+}
+
+// PluckContext builds an array of values form a property of a list of objects,
+// it stops early when the context is done
+func (j *JSONQ) PluckContext(ctx context.Context, property string) (interface{}, error) {
+	defer j.withContext(ctx)()
+	v := j.Pluck(property)
+	return v, j.contextError()
+}
+
+// readAll reads r up to the maximum input size
+func (j *JSONQ) readAll(r io.Reader) ([]byte, error) {
+	max := j.option.maxInputSize
+	if max > 0 {
+		r = io.LimitReader(r, max+1)
+	}
+	bb, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if max > 0 && int64(len(bb)) > max {
+		return nil, fmt.Errorf("%w of %d bytes", ErrInputTooLarge, max)
+	}
+	return bb, nil
+}
+
+// checkLimits checks the raw json content against the maximum input size and depth
+func (j *JSONQ) checkLimits(raw []byte) error {
+	if max := j.option.maxInputSize; max > 0 && int64(len(raw)) > max {
+		return fmt.Errorf("%w of %d bytes", ErrInputTooLarge, max)
+	}
+	if max := j.option.maxDepth; max > 0 && depthExceeds(raw, max) {
+		return fmt.Errorf("%w of %d", ErrTooDeep, max)
+	}
+	return nil
+}
+
+// depthExceeds checks whether the nesting of the objects and arrays of the json content exceeds max,
+// the content is scanned without being decoded
+func depthExceeds(raw []byte, max int) bool {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range raw {
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{' || c == '[':
+			if depth++; depth > max {
+				return true
+			}
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return false
+}
+
+// capRows truncates the list to the maximum result rows
+func (j *JSONQ) capRows() {
+	max := j.option.maxResultRows
+	if aa, ok := j.jsonContent.([]interface{}); ok && max > 0 && len(aa) > max {
+		j.jsonContent = aa[:max:max]
+		j.addError(fmt.Errorf("%w of %d", ErrTooManyRows, max))
+	}
+}
@@ -0,0 +1,142 @@
+package gojsonq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONQ_GetContext(t *testing.T) {
+	jq := New().FromString(jsonStr).From("vendor.items").Where("price", ">", 1000)
+	out, err := jq.GetContext(context.Background())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assertJSON(t, out, `[{"id":1,"name":"MacBook Pro 13 inch retina","price":1350},{"id":2,"name":"MacBook Pro 15 inch retina","price":1700},{"id":3,"name":"Sony VAIO","price":1200}]`, "get with context")
+}
+
+func TestJSONQ_Context_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		tag string
+		run func(jq *JSONQ) (interface{}, error)
+	}{
+		{tag: "get", run: func(jq *JSONQ) (interface{}, error) { return jq.GetContext(ctx) }},
+		{tag: "first", run: func(jq *JSONQ) (interface{}, error) { return jq.FirstContext(ctx) }},
+		{tag: "last", run: func(jq *JSONQ) (interface{}, error) { return jq.LastContext(ctx) }},
+		{tag: "find", run: func(jq *JSONQ) (interface{}, error) { return jq.FindContext(ctx, "name") }},
+		{tag: "count", run: func(jq *JSONQ) (interface{}, error) { return jq.CountContext(ctx) }},
+		{tag: "pluck", run: func(jq *JSONQ) (interface{}, error) { return jq.PluckContext(ctx, "id") }},
+	}
+	for _, tc := range testCases {
+		jq := New().FromString(jsonStr).From("vendor.items").Where("price", ">", 1000)
+		if _, err := tc.run(jq); !errors.Is(err, context.Canceled) {
+			t.Errorf("tag: %s, expected context canceled got: %v", tc.tag, err)
+		}
+	}
+}
+
+func TestJSONQ_WithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		tag      string
+		run      func(jq *JSONQ) interface{}
+		expected string
+	}{
+		{tag: "sort by", run: func(jq *JSONQ) interface{} { return jq.SortBy("price").Get() }, expected: `[]`},
+		{tag: "group by", run: func(jq *JSONQ) interface{} { return jq.GroupBy("price").Get() }, expected: `{}`},
+		{tag: "where", run: func(jq *JSONQ) interface{} { return jq.WhereNotNil("id").Get() }, expected: `[]`},
+	}
+	for _, tc := range testCases {
+		jq := New().FromString(jsonStr).From("vendor.items").WithContext(ctx)
+		assertJSON(t, tc.run(jq), tc.expected, tc.tag)
+		errs := jq.Errors()
+		if len(errs) != 1 || !errors.Is(errs[0], context.Canceled) {
+			t.Errorf("tag: %s, expected a single context error got: %v", tc.tag, errs)
+		}
+	}
+
+	jq := New().FromString(`[3,1,2]`).WithContext(ctx)
+	assertJSON(t, jq.Sort().Get(), `[3,1,2]`, "sort canceled")
+}
+
+func TestJSONQ_Context_canceled_while_sorting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	sm := &sortMap{key: "id", separator: ".", canceled: func() bool {
+		calls++
+		if calls == 2 {
+			cancel()
+		}
+		return ctx.Err() != nil
+	}}
+	list := make([]interface{}, 1000)
+	for i := range list {
+		list[i] = map[string]interface{}{"id": float64(len(list) - i)}
+	}
+	sm.Sort(list)
+	if !sm.stopped || calls != 2 {
+		t.Errorf("expected the sort to stop after the context is canceled, got %d checks", calls)
+	}
+}
+
+func TestWithMaxInputSize(t *testing.T) {
+	jq := New(WithMaxInputSize(10)).FromString(jsonStr)
+	if !errors.Is(jq.Error(), ErrInputTooLarge) {
+		t.Errorf("expected input too large from string, got: %v", jq.Error())
+	}
+	jq = New(WithMaxInputSize(10)).Reader(strings.NewReader(jsonStr))
+	if !errors.Is(jq.Error(), ErrInputTooLarge) {
+		t.Errorf("expected input too large from reader, got: %v", jq.Error())
+	}
+	jq = New(WithMaxInputSize(int64(len(jsonStr)))).Reader(strings.NewReader(jsonStr))
+	if jq.Error() != nil {
+		t.Errorf("unexpected error: %v", jq.Error())
+	}
+	if New(WithMaxInputSize(0)).Error() == nil {
+		t.Error("failed to catch invalid maximum input size")
+	}
+}
+
+func TestWithMaxDepth(t *testing.T) {
+	testCases := []struct {
+		tag     string
+		json    string
+		tooDeep bool
+	}{
+		{tag: "flat", json: `{"a":1}`},
+		{tag: "at the limit", json: `{"a":[{"b":1}]}`},
+		{tag: "too deep", json: `{"a":[{"b":[1]}]}`, tooDeep: true},
+		{tag: "brackets in strings", json: `{"a":"[[[{{{","b":"\"[[["}`},
+	}
+	for _, tc := range testCases {
+		jq := New(WithMaxDepth(3)).FromString(tc.json)
+		if errors.Is(jq.Error(), ErrTooDeep) != tc.tooDeep {
+			t.Errorf("tag: %s, unexpected error: %v", tc.tag, jq.Error())
+		}
+	}
+	if New(WithMaxDepth(-1)).Error() == nil {
+		t.Error("failed to catch invalid maximum depth")
+	}
+}
+
+func TestWithMaxResultRows(t *testing.T) {
+	jq := New(WithMaxResultRows(2)).FromString(jsonStr).From("vendor.items").Where("price", "<", 1000)
+	assertJSON(t, jq.Pluck("id"), `[4,5]`, "truncated rows")
+	if errs := jq.Errors(); len(errs) != 1 || !errors.Is(errs[0], ErrTooManyRows) {
+		t.Errorf("expected too many rows, got: %v", errs)
+	}
+
+	jq = New(WithMaxResultRows(7)).FromString(jsonStr).From("vendor.items")
+	if jq.Count() != 7 || jq.Error() != nil {
+		t.Errorf("unexpected error: %v", jq.Error())
+	}
+	if New(WithMaxResultRows(0)).Error() == nil {
+		t.Error("failed to catch invalid maximum result rows")
+	}
+}
@@ -0,0 +1,173 @@
+package gojsonq
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArrayMergeStrategy describes how Merge combines two arrays, see WithArrayMerge
+type ArrayMergeStrategy int
+
+// Available array merge strategies
+const (
+	// ArrayReplace replaces the array by the array of the later source
+	ArrayReplace ArrayMergeStrategy = iota
+	// ArrayAppend appends the elements of the later source to the array
+	ArrayAppend
+	// ArrayMergeByKey deep merges the objects having the same value of the key path and appends the others
+	ArrayMergeByKey
+)
+
+// Merge deep merges the query results of the sources into the content of j, later sources take precedence:
+// objects are merged key by key, arrays are combined following WithArrayMerge and any other value is replaced.
+// The sources are left untouched. Origin reports the source of each value, the content of j being the source 0.
+// e.g: New().File("defaults.json").Merge(env, local)
+func (j *JSONQ) Merge(sources ...*JSONQ) *JSONQ {
+	base := j.Copy()
+	return j.FromMerged(append([]*JSONQ{base}, sources...)...)
+}
+
+// FromMerged reads the content from the deep merge of the query results of the sources, see Merge.
+// Origin reports the position of the source of each value. e.g: New().FromMerged(defaults, env, local)
+func (j *JSONQ) FromMerged(sources ...*JSONQ) *JSONQ {
+	m := &merger{
+		separator: j.option.separator,
+		strategy:  j.option.arrayMerge,
+		key:       j.option.arrayMergeKey,
+		origins:   map[string]int{},
+	}
+	var root interface{}
+	for i, src := range sources {
+		v := src.Get()
+		if err := src.Error(); err != nil {
+			return j.addError(fmt.Errorf("merge source %d: %w", i, err))
+		}
+		if i == 0 {
+			root = m.set(v, "", i)
+			continue
+		}
+		root = m.merge(root, v, "", i)
+	}
+
+	j.raw = nil
+	j.rootJSONContent = root
+	j.jsonContent = j.rootJSONContent
+	j.origins = m.origins
+	return j
+}
+
+// Origin returns the position of the source the value at path comes from after Merge or FromMerged,
+// for an object or an array it is the last source which changed it. ok is false for an unknown path
+func (j *JSONQ) Origin(path string) (source int, ok bool) {
+	source, ok = j.origins[path]
+	return source, ok
+}
+
+// merger deep merges decoded json values and records the source of each value by path
+type merger struct {
+	separator string
+	strategy  ArrayMergeStrategy
+	key       string
+	origins   map[string]int
+}
+
+// merge returns the merge of src into dst, neither of them is modified
+func (m *merger) merge(dst, src interface{}, path string, source int) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			break
+		}
+		merged := make(map[string]interface{}, len(d)+len(s))
+		for k, v := range d {
+			merged[k] = v
+		}
+		for k, v := range s {
+			p := joinKey(path, k, m.separator)
+			if dv, ok := d[k]; ok {
+				merged[k] = m.merge(dv, v, p, source)
+			} else {
+				merged[k] = m.set(v, p, source)
+			}
+		}
+		m.origins[path] = source
+		return merged
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok || m.strategy == ArrayReplace {
+			break
+		}
+		merged := append(make([]interface{}, 0, len(d)+len(s)), d...)
+		for _, v := range s {
+			if m.strategy == ArrayMergeByKey {
+				if i, ok := m.find(merged, v); ok {
+					merged[i] = m.merge(merged[i], v, joinKey(path, indexSegment(i), m.separator), source)
+					continue
+				}
+			}
+			merged = append(merged, m.set(v, joinKey(path, indexSegment(len(merged)), m.separator), source))
+		}
+		m.origins[path] = source
+		return merged
+	}
+	if isCollection(dst) {
+		m.forget(path)
+	}
+	return m.set(src, path, source)
+}
+
+// find returns the position of the object of list having the same key as v
+func (m *merger) find(list []interface{}, v interface{}) (int, bool) {
+	if _, ok := v.(map[string]interface{}); !ok {
+		return 0, false
+	}
+	key, err := getNestedValue(v, m.key, m.separator)
+	if err != nil {
+		return 0, false
+	}
+	for i, e := range list {
+		if _, ok := e.(map[string]interface{}); !ok {
+			continue
+		}
+		if ek, err := getNestedValue(e, m.key, m.separator); err == nil && hashKey(ek) == hashKey(key) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// set returns a copy of v, recording the source of v and of its nested values
+func (m *merger) set(v interface{}, path string, source int) interface{} {
+	m.origins[path] = source
+	switch x := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			c[k] = m.set(e, joinKey(path, k, m.separator), source)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(x))
+		for i, e := range x {
+			c[i] = m.set(e, joinKey(path, indexSegment(i), m.separator), source)
+		}
+		return c
+	case map[string][]interface{}:
+		c := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			c[k] = m.set(e, joinKey(path, k, m.separator), source)
+		}
+		return c
+	}
+	return v
+}
+
+// forget removes the sources recorded for the replaced value at path and its nested values
+func (m *merger) forget(path string) {
+	for p := range m.origins {
+		if path == "" || p == path || strings.HasPrefix(p, path+m.separator) {
+			delete(m.origins, p)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package gojsonq
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	mergeDefaults = `{"server":{"host":"localhost","port":8080,"tls":{"enabled":false}},"features":["a","b"],
+		"users":[{"id":1,"name":"admin","roles":["read"]},{"id":2,"name":"guest"}],"debug":true}`
+	mergeEnv   = `{"server":{"port":9090,"tls":{"enabled":true,"cert":"/etc/cert"}},"features":["c"],"users":[{"id":2,"name":"visitor"},{"id":3,"name":"ops"}]}`
+	mergeLocal = `{"server":{"tls":"off"},"debug":null}`
+)
+
+func TestJSONQ_FromMerged(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		options  []OptionFunc
+		path     string
+		expected string
+	}{
+		{
+			tag:      "deep merge of objects",
+			path:     "server",
+			expected: `{"host":"localhost","port":9090,"tls":"off"}`,
+		},
+		{
+			tag:      "null replaces",
+			path:     "debug",
+			expected: `null`,
+		},
+		{
+			tag:      "arrays are replaced by default",
+			path:     "features",
+			expected: `["c"]`,
+		},
+		{
+			tag:      "arrays appended",
+			options:  []OptionFunc{WithArrayMerge(ArrayAppend)},
+			path:     "features",
+			expected: `["a","b","c"]`,
+		},
+		{
+			tag:      "arrays merged by key",
+			options:  []OptionFunc{WithArrayMerge(ArrayMergeByKey, "id")},
+			path:     "users",
+			expected: `[{"id":1,"name":"admin","roles":["read"]},{"id":2,"name":"visitor"},{"id":3,"name":"ops"}]`,
+		},
+	}
+	for _, tc := range testCases {
+		jq := New(tc.options...).FromMerged(
+			New().FromString(mergeDefaults),
+			New().FromString(mergeEnv),
+			New().FromString(mergeLocal),
+		)
+		assertJSON(t, jq.Find(tc.path), tc.expected, tc.tag)
+	}
+}
+
+func TestJSONQ_Merge_origin(t *testing.T) {
+	defaults := New().FromString(mergeDefaults)
+	jq := New(WithArrayMerge(ArrayMergeByKey, "id")).FromString(mergeEnv).
+		Merge(New().FromString(mergeLocal), defaults.From("server"))
+
+	testCases := []struct {
+		path   string
+		source int
+		ok     bool
+	}{
+		{path: "server.port", source: 0, ok: true},
+		{path: "server.tls", source: 1, ok: true},
+		{path: "server.tls.cert", ok: false}, // replaced by a value of source 1
+		{path: "users.[1].name", source: 0, ok: true},
+		{path: "debug", source: 1, ok: true},
+		{path: "port", source: 2, ok: true},
+		{path: "host", source: 2, ok: true},
+		{path: "", source: 2, ok: true},
+		{path: "missing", ok: false},
+	}
+	for _, tc := range testCases {
+		source, ok := jq.Origin(tc.path)
+		if ok != tc.ok || source != tc.source {
+			t.Errorf("Origin(%q): expected %d %v, got %d %v", tc.path, tc.source, tc.ok, source, ok)
+		}
+	}
+
+	// the sources are left untouched
+	assertJSON(t, defaults.Reset().Find("server.port"), `8080`, "merge source untouched")
+}
+
+func TestJSONQ_Merge_origin_by_key_and_append(t *testing.T) {
+	jq := New(WithArrayMerge(ArrayMergeByKey, "id"), WithSeparator("/")).FromMerged(
+		New().FromString(mergeDefaults),
+		New().FromString(mergeEnv),
+	)
+	for path, expected := range map[string]int{"users/[0]/name": 0, "users/[1]/name": 1, "users/[2]/id": 1, "users/[0]/roles/[0]": 0} {
+		if source, ok := jq.Origin(path); !ok || source != expected {
+			t.Errorf("Origin(%q): expected %d, got %d %v", path, expected, source, ok)
+		}
+	}
+
+	jq = New(WithArrayMerge(ArrayAppend)).FromMerged(New().FromString(mergeDefaults), New().FromString(mergeEnv))
+	if source, ok := jq.Origin("features.[2]"); !ok || source != 1 {
+		t.Errorf("Origin of appended element: expected 1, got %d %v", source, ok)
+	}
+}
+
+func TestJSONQ_Merge_errors(t *testing.T) {
+	jq := New().FromMerged(New().FromString(mergeDefaults), New().FromString(mergeEnv).From("missing"))
+	if jq.Error() == nil || !strings.Contains(jq.Error().Error(), "merge source 1") {
+		t.Errorf("failed to catch merge source error: %v", jq.Error())
+	}
+
+	if jq := New(WithArrayMerge(ArrayMergeByKey)); jq.Error() == nil {
+		t.Error("failed to catch missing key path")
+	}
+	if jq := New(WithArrayMerge(ArrayMergeStrategy(7))); jq.Error() == nil {
+		t.Error("failed to catch invalid strategy")
+	}
+}
@@ -1,11 +1,32 @@
 package gojsonq
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // option describes type for providing configuration options to JSONQ
 type option struct {
 	decoder   Decoder
+	encoder   Encoder
 	separator string
+	tracing   bool
+
+	missingKey   MissingKeyPolicy
+	typeMismatch TypeMismatchPolicy
+
+	maxInputSize  int64
+	maxDepth      int
+	maxResultRows int
+
+	parallelism int
+
+	coercion bool
+
+	arrayMerge    ArrayMergeStrategy
+	arrayMergeKey string
+
+	decompression bool
 }
 
 // OptionFunc represents a contract for option func, it basically set options to jsonq instance options
@@ -34,6 +55,17 @@ func WithDecoder(u Decoder) OptionFunc {
 	}
 }
 
+// WithEncoder take a custom encoder used by Writer, e.g: &PrettyEncoder{}, &YAMLEncoder{}, &CSVEncoder{}, &NDJSONEncoder{}
+func WithEncoder(e Encoder) OptionFunc {
+	return func(j *JSONQ) error {
+		if e == nil {
+			return errors.New("encoder can not be nil")
+		}
+		j.option.encoder = e
+		return nil
+	}
+}
+
 // WithSeparator set custom separator for traversing child node, default separator is DOT (.)
 func WithSeparator(s string) OptionFunc {
 	return func(j *JSONQ) error {
@@ -44,3 +76,118 @@ func WithSeparator(s string) OptionFunc {
 		return nil
 	}
 }
+
+// WithTracing records the statistics of the where clauses, which are available by Trace after running the query
+func WithTracing() OptionFunc {
+	return func(j *JSONQ) error {
+		j.option.tracing = true
+		return nil
+	}
+}
+
+// WithMissingKeyPolicy sets how Where, SortBy, GroupBy, Distinct, Select and the aggregations handle
+// the rows missing a key, default MissingKeyError
+func WithMissingKeyPolicy(p MissingKeyPolicy) OptionFunc {
+	return func(j *JSONQ) error {
+		if p < MissingKeyError || p > MissingKeyTreatAsNull {
+			return fmt.Errorf("%d is invalid missing key policy", p)
+		}
+		j.option.missingKey = p
+		return nil
+	}
+}
+
+// WithTypeMismatchPolicy sets how Where, SortBy, Select and the aggregations handle the values
+// of unexpected type, default TypeMismatchError
+func WithTypeMismatchPolicy(p TypeMismatchPolicy) OptionFunc {
+	return func(j *JSONQ) error {
+		if p < TypeMismatchError || p > TypeMismatchIgnore {
+			return fmt.Errorf("%d is invalid type mismatch policy", p)
+		}
+		j.option.typeMismatch = p
+		return nil
+	}
+}
+
+// WithMaxInputSize limits the size of the json content in bytes read by File, Reader and FromString
+func WithMaxInputSize(n int64) OptionFunc {
+	return func(j *JSONQ) error {
+		if n <= 0 {
+			return fmt.Errorf("%d is invalid maximum input size", n)
+		}
+		j.option.maxInputSize = n
+		return nil
+	}
+}
+
+// WithMaxDepth limits the nesting depth of the objects and arrays of the json content
+func WithMaxDepth(n int) OptionFunc {
+	return func(j *JSONQ) error {
+		if n <= 0 {
+			return fmt.Errorf("%d is invalid maximum depth", n)
+		}
+		j.option.maxDepth = n
+		return nil
+	}
+}
+
+// WithMaxResultRows limits the number of rows produced by a query before Offset and Limit,
+// the rows are truncated and an error is reported when the limit is exceeded
+func WithMaxResultRows(n int) OptionFunc {
+	return func(j *JSONQ) error {
+		if n <= 0 {
+			return fmt.Errorf("%d is invalid maximum result rows", n)
+		}
+		j.option.maxResultRows = n
+		return nil
+	}
+}
+
+// WithParallelism evaluates the where clauses of large lists on n goroutines, the list is split
+// into n contiguous parts and the matched rows are merged in the original order
+func WithParallelism(n int) OptionFunc {
+	return func(j *JSONQ) error {
+		if n < 1 {
+			return fmt.Errorf("%d is invalid parallelism", n)
+		}
+		j.option.parallelism = n
+		return nil
+	}
+}
+
+// WithCoercion makes the accessors of the results converted between numeric strings, numbers,
+// booleans and RFC 3339 times, e.g: Int of "42" returns 42 instead of an error
+func WithCoercion() OptionFunc {
+	return func(j *JSONQ) error {
+		j.option.coercion = true
+		return nil
+	}
+}
+
+// WithArrayMerge sets how Merge and FromMerged combine two arrays, default ArrayReplace.
+// ArrayMergeByKey requires the key path identifying the objects, e.g: WithArrayMerge(ArrayMergeByKey, "id")
+func WithArrayMerge(s ArrayMergeStrategy, keyPath ...string) OptionFunc {
+	return func(j *JSONQ) error {
+		if s < ArrayReplace || s > ArrayMergeByKey {
+			return fmt.Errorf("%d is invalid array merge strategy", s)
+		}
+		if s == ArrayMergeByKey && (len(keyPath) != 1 || keyPath[0] == "") {
+			return errors.New("array merge by key requires one key path")
+		}
+		j.option.arrayMerge = s
+		j.option.arrayMergeKey = ""
+		if len(keyPath) > 0 {
+			j.option.arrayMergeKey = keyPath[0]
+		}
+		return nil
+	}
+}
+
+// WithDecompression enables or disables the detection of gzip, zlib and bzip2 content by File, Reader,
+// FromFS and FromGlob, enabled by default. The maximum input size applies to the decompressed content
+func WithDecompression(enabled bool) OptionFunc {
+	return func(j *JSONQ) error {
+		j.option.decompression = enabled
+		return nil
+	}
+}
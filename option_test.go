@@ -60,3 +60,67 @@ func TestSetSeparator_with_nil_expecting_an_error(t *testing.T) {
 		t.Error("failed to catch nil in SetSeparator")
 	}
 }
+
+func TestWithTracing(t *testing.T) {
+	jq := New(WithTracing())
+	if !jq.option.tracing {
+		t.Error("failed to set tracing as option")
+	}
+}
+
+func TestWithMissingKeyPolicy(t *testing.T) {
+	jq := New(WithMissingKeyPolicy(MissingKeyTreatAsNull))
+	if jq.option.missingKey != MissingKeyTreatAsNull {
+		t.Error("failed to set missing key policy as option")
+	}
+	if New(WithMissingKeyPolicy(MissingKeyPolicy(9))).Error() == nil {
+		t.Error("failed to catch invalid missing key policy")
+	}
+}
+
+func TestWithTypeMismatchPolicy(t *testing.T) {
+	jq := New(WithTypeMismatchPolicy(TypeMismatchIgnore))
+	if jq.option.typeMismatch != TypeMismatchIgnore {
+		t.Error("failed to set type mismatch policy as option")
+	}
+	if New(WithTypeMismatchPolicy(TypeMismatchPolicy(-1))).Error() == nil {
+		t.Error("failed to catch invalid type mismatch policy")
+	}
+}
+
+func TestWithCoercion(t *testing.T) {
+	jq := New(WithCoercion())
+	if !jq.option.coercion {
+		t.Error("failed to set coercion as option")
+	}
+}
+
+func TestWithEncoder(t *testing.T) {
+	jq := New(WithEncoder(&YAMLEncoder{}))
+	if _, ok := jq.option.encoder.(*YAMLEncoder); !ok {
+		t.Error("failed to set encoder as option")
+	}
+}
+
+func TestWithEncoder_with_nil_expecting_an_error(t *testing.T) {
+	jq := New(WithEncoder(nil))
+	if jq.Error() == nil {
+		t.Error("failed to catch nil in WithEncoder")
+	}
+}
+
+func TestWithArrayMerge(t *testing.T) {
+	jq := New(WithArrayMerge(ArrayMergeByKey, "id"))
+	if jq.option.arrayMerge != ArrayMergeByKey || jq.option.arrayMergeKey != "id" {
+		t.Error("failed to set array merge strategy as option")
+	}
+}
+
+func TestWithDecompression(t *testing.T) {
+	if !New().option.decompression {
+		t.Error("decompression must be enabled by default")
+	}
+	if New(WithDecompression(false)).option.decompression {
+		t.Error("failed to disable decompression as option")
+	}
+}
@@ -0,0 +1,71 @@
+package gojsonq
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// parallelMinRows is the minimum number of rows scanned in parallel, smaller lists are scanned
+// sequentially as starting the goroutines costs more than it saves
+const parallelMinRows = 1024
+
+// scanParallel returns the rows at positions matching the groups using the configured number of
+// goroutines. Every goroutine scans a contiguous part of the positions with its own errors and
+// statistics, which are merged in order so that the result is the same as a sequential scan
+func (j *JSONQ) scanParallel(aa []interface{}, positions []int, groups [][]predicate) []interface{} {
+	n := j.option.parallelism
+	if n > len(positions) {
+		n = len(positions)
+	}
+	size := (len(positions) + n - 1) / n
+	// the size is rounded up, fewer parts may be enough to cover the positions
+	n = (len(positions) + size - 1) / size
+	workers := make([]*JSONQ, n)
+	results := make([][]interface{}, n)
+
+	var wg sync.WaitGroup
+	for w := 0; w < n; w++ {
+		from, to := w*size, (w+1)*size
+		if to > len(positions) {
+			to = len(positions)
+		}
+		workers[w] = j.worker()
+		wg.Add(1)
+		go func(w int, part []int) {
+			defer wg.Done()
+			results[w] = workers[w].scan(aa, part, groups)
+		}(w, positions[from:to])
+	}
+	wg.Wait()
+
+	result := make([]interface{}, 0)
+	for w, worker := range workers {
+		for _, err := range worker.errors {
+			// the error of the context is reported once below
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+				j.errors = append(j.errors, err)
+			}
+		}
+		if j.trace != nil {
+			j.trace.merge(worker.trace)
+		}
+		result = append(result, results[w]...)
+	}
+	if j.canceled() {
+		return make([]interface{}, 0)
+	}
+	return result
+}
+
+// worker returns a JSONQ instance scanning a part of the list on its own goroutine
+func (j *JSONQ) worker() *JSONQ {
+	w := &JSONQ{option: j.option, ctx: j.ctx}
+	if j.trace != nil {
+		w.trace = &Trace{Groups: make([][]PredicateTrace, len(j.trace.Groups))}
+		for gi, group := range j.trace.Groups {
+			w.trace.Groups[gi] = make([]PredicateTrace, len(group))
+		}
+	}
+	return w
+}
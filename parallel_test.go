@@ -0,0 +1,108 @@
+package gojsonq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// parallelItems returns n items, every third one misses the qty key
+func parallelItems(n int) string {
+	items := make([]string, n)
+	for i := range items {
+		if i%3 == 0 {
+			items[i] = fmt.Sprintf(`{"id":%d}`, i)
+			continue
+		}
+		items[i] = fmt.Sprintf(`{"id":%d,"qty":%d}`, i, i%10)
+	}
+	return `{"items":[` + strings.Join(items, ",") + `]}`
+}
+
+func TestWithParallelism(t *testing.T) {
+	// 1025 rows split in 64 parts of 17 rows need only 61 parts
+	for _, rows := range []int{5000, 1025} {
+		testParallelism(t, rows)
+	}
+}
+
+func testParallelism(t *testing.T, rows int) {
+	data := parallelItems(rows)
+	query := func(jq *JSONQ) *JSONQ {
+		return jq.From("items").Where("qty", ">", 4).OrWhere("id", "<", 10)
+	}
+
+	seq := query(New(WithTracing()).FromString(data))
+	expected := seq.Pluck("id")
+
+	for _, n := range []int{2, 3, 7, 8, 64} {
+		par := query(New(WithParallelism(n), WithTracing()).FromString(data))
+		assertInterface(t, par.Pluck("id"), expected, fmt.Sprintf("%d rows, parallelism %d", rows, n))
+
+		// errors are reported in the order of the rows
+		if len(par.Errors()) != len(seq.Errors()) {
+			t.Fatalf("%d rows, parallelism %d: expected %d errors got: %d", rows, n, len(seq.Errors()), len(par.Errors()))
+		}
+		for i, err := range par.Errors() {
+			if err.Error() != seq.Errors()[i].Error() {
+				t.Errorf("%d rows, parallelism %d: expected error %v got: %v", rows, n, seq.Errors()[i], err)
+				break
+			}
+		}
+
+		st, pt := seq.Trace(), par.Trace()
+		if st.Rows != pt.Rows || st.Matched != pt.Matched {
+			t.Errorf("%d rows, parallelism %d: expected %d/%d rows got: %d/%d", rows, n, st.Rows, st.Matched, pt.Rows, pt.Matched)
+		}
+		for gi := range st.Groups {
+			for pi := range st.Groups[gi] {
+				s, p := st.Groups[gi][pi], pt.Groups[gi][pi]
+				if s.Passed != p.Passed || s.Failed != p.Failed || s.Errors != p.Errors {
+					t.Errorf("%d rows, parallelism %d: expected %+v got: %+v", rows, n, s, p)
+				}
+			}
+		}
+	}
+}
+
+func TestWithParallelism_small_lists_are_scanned_sequentially(t *testing.T) {
+	jq := New(WithParallelism(4)).FromString(jsonStr).From("vendor.items").Where("price", ">", 1000)
+	assertJSON(t, jq.Pluck("id"), `[1,2,3]`, "small list with parallelism")
+	if New(WithParallelism(0)).Error() == nil {
+		t.Error("failed to catch invalid parallelism")
+	}
+}
+
+func TestWithParallelism_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	jq := New(WithParallelism(4)).FromString(parallelItems(5000)).From("items").WhereNotNil("id")
+	out, err := jq.GetContext(ctx)
+	assertJSON(t, out, `[]`, "parallel scan canceled")
+	if errs := jq.Errors(); !errors.Is(err, context.Canceled) || len(errs) != 1 {
+		t.Errorf("expected a single context error, got: %v", errs)
+	}
+}
+
+func benchmarkWhere(b *testing.B, options ...OptionFunc) {
+	jq := New(options...).FromString(benchmarkItems(100000))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		jq.Reset()
+		jq.From("items").Where("id", ">", 100).WhereEndsWith("sku", "5").Get()
+	}
+}
+
+func Benchmark_Where_sequential(b *testing.B) {
+	benchmarkWhere(b)
+}
+
+func Benchmark_Where_parallel_4(b *testing.B) {
+	benchmarkWhere(b, WithParallelism(4))
+}
+
+func Benchmark_Where_parallel_16(b *testing.B) {
+	benchmarkWhere(b, WithParallelism(16))
+}
@@ -0,0 +1,55 @@
+package gojsonq
+
+import "errors"
+
+// MissingKeyPolicy describes how the rows missing a key are handled
+type MissingKeyPolicy int
+
+const (
+	// MissingKeyError reports an error for every row missing the key and skips the row, default
+	MissingKeyError MissingKeyPolicy = iota
+	// MissingKeyIgnore skips the rows missing the key without reporting any error
+	MissingKeyIgnore
+	// MissingKeyTreatAsNull uses null as the value of the missing key
+	MissingKeyTreatAsNull
+)
+
+// TypeMismatchPolicy describes how the values of unexpected type are handled, e.g: a string compared with ">"
+type TypeMismatchPolicy int
+
+const (
+	// TypeMismatchError reports an error for every value of unexpected type and skips it, default
+	TypeMismatchError TypeMismatchPolicy = iota
+	// TypeMismatchIgnore skips the values of unexpected type without reporting any error
+	TypeMismatchIgnore
+)
+
+// isMissingKey checks whether err is caused by a missing key or array element
+func isMissingKey(err error) bool {
+	return errors.Is(err, ErrInvalidNode) || errors.Is(err, ErrIndexOutOfRange)
+}
+
+// rowError applies the policies to an error occurred while processing a row. It returns the value to use
+// instead and whether the value can be used, errors not covered by the policies are always reported
+func (j *JSONQ) rowError(err error) (interface{}, bool) {
+	var te *TypeError
+	switch {
+	case isMissingKey(err) && j.option.missingKey == MissingKeyTreatAsNull:
+		return nil, true
+	case isMissingKey(err) && j.option.missingKey == MissingKeyIgnore:
+	case errors.As(err, &te) && j.option.typeMismatch == TypeMismatchIgnore:
+	default:
+		j.addError(err)
+	}
+	return nil, false
+}
+
+// lookup returns the value of the row at the path applying the policies,
+// the row has to be skipped when the value can not be used
+func (j *JSONQ) lookup(row interface{}, path string) (interface{}, bool) {
+	v, err := getNestedValue(row, path, j.option.separator)
+	if err != nil {
+		return j.rowError(err)
+	}
+	return v, true
+}
@@ -0,0 +1,172 @@
+package gojsonq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMissingKeyPolicy(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		query    func(jq *JSONQ) interface{}
+		expected [3]string // result per policy: error, ignore, treat as null
+		errors   int       // number of errors with MissingKeyError
+	}{
+		{
+			tag:      "where",
+			query:    func(jq *JSONQ) interface{} { return jq.WhereNil("key").Pluck("id") },
+			expected: [3]string{`[]`, `[]`, `[1,2,3,4,6,null]`},
+			errors:   6,
+		},
+		{
+			tag:      "group by",
+			query:    func(jq *JSONQ) interface{} { return jq.GroupBy("key").Count() },
+			expected: [3]string{`1`, `1`, `2`},
+			errors:   6,
+		},
+		{
+			tag:      "distinct",
+			query:    func(jq *JSONQ) interface{} { return jq.Distinct("key").Count() },
+			expected: [3]string{`1`, `1`, `2`},
+			errors:   6,
+		},
+		{
+			tag:      "select",
+			query:    func(jq *JSONQ) interface{} { return jq.WhereEqual("id", 4).Select("id", "key").Get() },
+			expected: [3]string{`[{"id":4}]`, `[{"id":4}]`, `[{"id":4,"key":null}]`},
+			errors:   1,
+		},
+		{
+			tag:      "sort by",
+			query:    func(jq *JSONQ) interface{} { return jq.WhereIn("id", []int{1, 5}).SortBy("key").Pluck("id") },
+			expected: [3]string{`[1,5]`, `[5]`, `[1,5]`},
+			errors:   1,
+		},
+		{
+			tag:      "sort by descending",
+			query:    func(jq *JSONQ) interface{} { return jq.WhereIn("id", []int{5, 1}).SortBy("key", "desc").Pluck("id") },
+			expected: [3]string{`[5,1]`, `[5]`, `[5,1]`},
+			errors:   1,
+		},
+		{
+			tag:      "sum",
+			query:    func(jq *JSONQ) interface{} { return jq.Sum("key") },
+			expected: [3]string{`0`, `2300`, `2300`},
+			errors:   1,
+		},
+	}
+
+	policies := []MissingKeyPolicy{MissingKeyError, MissingKeyIgnore, MissingKeyTreatAsNull}
+	for _, tc := range testCases {
+		for i, p := range policies {
+			jq := New(WithMissingKeyPolicy(p)).FromString(jsonStr).From("vendor.items")
+			assertJSON(t, tc.query(jq), tc.expected[i], tc.tag)
+
+			errs := jq.Errors()
+			switch p {
+			case MissingKeyError:
+				if len(errs) != tc.errors || !errors.Is(errs[0], ErrInvalidNode) {
+					t.Errorf("tag: %s, expected %d missing key errors got: %v", tc.tag, tc.errors, errs)
+				}
+			case MissingKeyIgnore, MissingKeyTreatAsNull:
+				if len(errs) != 0 {
+					t.Errorf("tag: %s, expected no errors got: %v", tc.tag, errs)
+				}
+			}
+		}
+	}
+}
+
+func TestMissingKeyPolicy_sort_by_order(t *testing.T) {
+	data := `[{"a":2},{"b":1},{"a":1}]`
+	jq := New(WithMissingKeyPolicy(MissingKeyIgnore)).FromString(data)
+	assertJSON(t, jq.SortBy("a").Get(), `[{"a":1},{"a":2}]`, "sort by skipping the missing keys")
+
+	jq = New(WithMissingKeyPolicy(MissingKeyTreatAsNull)).FromString(data)
+	assertJSON(t, jq.SortBy("a").Get(), `[{"b":1},{"a":1},{"a":2}]`, "sort by with null first")
+	jq = New(WithMissingKeyPolicy(MissingKeyTreatAsNull)).FromString(data)
+	assertJSON(t, jq.SortBy("a", "desc").Get(), `[{"a":2},{"a":1},{"b":1}]`, "sort by descending with null last")
+}
+
+func TestMissingKeyPolicy_aggregation_skips_null(t *testing.T) {
+	data := `[{"a":4},{"a":null},{"b":2},{"a":2}]`
+	testCases := []struct {
+		tag      string
+		query    func(jq *JSONQ) interface{}
+		expected string
+	}{
+		{tag: "sum", query: func(jq *JSONQ) interface{} { return jq.Sum("a") }, expected: `6`},
+		{tag: "avg", query: func(jq *JSONQ) interface{} { return jq.Avg("a") }, expected: `3`},
+		{tag: "min", query: func(jq *JSONQ) interface{} { return jq.Min("a") }, expected: `2`},
+		{tag: "max", query: func(jq *JSONQ) interface{} { return jq.Max("a") }, expected: `4`},
+	}
+	for _, tc := range testCases {
+		jq := New(WithMissingKeyPolicy(MissingKeyTreatAsNull)).FromString(data)
+		assertJSON(t, tc.query(jq), tc.expected, tc.tag)
+		if jq.Error() != nil {
+			t.Errorf("tag: %s, unexpected error: %v", tc.tag, jq.Error())
+		}
+	}
+}
+
+func TestTypeMismatchPolicy(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		query    func(jq *JSONQ) interface{}
+		expected [2]string // result per policy: error, ignore
+		errors   int       // number of errors with TypeMismatchError
+	}{
+		{
+			tag:      "where",
+			query:    func(jq *JSONQ) interface{} { return jq.Where("name", ">", 1).Count() },
+			expected: [2]string{`0`, `0`},
+			errors:   7,
+		},
+		{
+			tag:      "select",
+			query:    func(jq *JSONQ) interface{} { return jq.WhereEqual("id", 4).Select("id", "name * 2 as double").Get() },
+			expected: [2]string{`[{"id":4}]`, `[{"id":4}]`},
+			errors:   1,
+		},
+		{
+			tag:      "sort by",
+			query:    func(jq *JSONQ) interface{} { return jq.SortBy("tags").Pluck("id") },
+			expected: [2]string{`[1,2]`, `[1,2]`},
+			errors:   1,
+		},
+		{
+			tag:      "sum",
+			query:    func(jq *JSONQ) interface{} { return jq.Sum("price") },
+			expected: [2]string{`0`, `1350`},
+			errors:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		for i, p := range []TypeMismatchPolicy{TypeMismatchError, TypeMismatchIgnore} {
+			jq := New(WithTypeMismatchPolicy(p)).FromString(`{"items":[{"id":1,"name":"a","price":1350,"tags":"t"},{"id":2,"name":"b","price":"free","tags":["x"]}]}`).From("items")
+			if tc.tag == "where" || tc.tag == "select" {
+				jq = New(WithTypeMismatchPolicy(p)).FromString(jsonStr).From("vendor.items")
+			}
+			assertJSON(t, tc.query(jq), tc.expected[i], tc.tag)
+
+			var te *TypeError
+			errs := jq.Errors()
+			if p == TypeMismatchError && (len(errs) != tc.errors || !errors.As(errs[0], &te)) {
+				t.Errorf("tag: %s, expected %d type errors got: %v", tc.tag, tc.errors, errs)
+			}
+			if p == TypeMismatchIgnore && len(errs) != 0 {
+				t.Errorf("tag: %s, expected no errors got: %v", tc.tag, errs)
+			}
+		}
+	}
+}
+
+func TestPolicies_other_errors_are_reported(t *testing.T) {
+	jq := New(WithMissingKeyPolicy(MissingKeyIgnore), WithTypeMismatchPolicy(TypeMismatchIgnore)).
+		FromString(jsonStr).From("vendor.items").Where("id", "invalid", 1)
+	jq.Get()
+	if !errors.Is(jq.Error(), ErrInvalidOperator) {
+		t.Errorf("expected invalid operator error, got: %v", jq.Error())
+	}
+}
@@ -0,0 +1,99 @@
+package gojsonq
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PreparedQuery describes a query compiled once and run against many documents.
+// Query functions are resolved, paths are split and Select expressions are parsed at compile time.
+// A PreparedQuery is immutable, Run can be called concurrently
+type PreparedQuery struct {
+	option     option
+	node       string
+	predicates [][]predicate
+	distinct   string
+	sortBy     []string
+	columns    []column
+	offset     int
+	limit      int
+}
+
+// Compile freezes the query into a PreparedQuery. The query functions are resolved from the
+// document the query is bound to, see NewQuery to build a query without any document
+func (q Query) Compile() (*PreparedQuery, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if q.doc == nil {
+		return nil, errors.New("gojsonq: query is not bound to any document")
+	}
+	j := &JSONQ{option: q.doc.option, queryMap: q.doc.queryMap, queries: q.queries}
+	predicates, err := j.compileQueries()
+	if err != nil {
+		return nil, fmt.Errorf("gojsonq: %w", err)
+	}
+	if q.sortBy != nil {
+		if _, _, err := sortByOrder(q.sortBy); err != nil {
+			return nil, fmt.Errorf("gojsonq: %w", err)
+		}
+	}
+	if q.offset < 0 {
+		return nil, fmt.Errorf("gojsonq: %d is invalid offset", q.offset)
+	}
+	if q.limit < 0 {
+		return nil, fmt.Errorf("gojsonq: %d is invalid limit", q.limit)
+	}
+	return &PreparedQuery{
+		option:     q.doc.option,
+		node:       q.node,
+		predicates: predicates,
+		distinct:   q.distinct,
+		sortBy:     q.sortBy,
+		columns:    j.columns(q.attributes),
+		offset:     q.offset,
+		limit:      q.limit,
+	}, nil
+}
+
+// Run runs the prepared query against the document and returns the result
+func (pq *PreparedQuery) Run(doc *Document) (interface{}, error) {
+	j := pq.build(doc)
+	v := j.Get()
+	return v, j.Error()
+}
+
+// RunR runs the prepared query against the document and returns the result as Result instance
+func (pq *PreparedQuery) RunR(doc *Document) (*Result, error) {
+	return pq.build(doc).GetR()
+}
+
+// build returns a new JSONQ instance over the document with the prepared query applied
+func (pq *PreparedQuery) build(doc *Document) *JSONQ {
+	if doc == nil {
+		return New().addError(errors.New("document can not be nil"))
+	}
+	j := &JSONQ{
+		option:           pq.option,
+		queryMap:         doc.queryMap,
+		rootJSONContent:  doc.content,
+		jsonContent:      doc.content,
+		predicates:       pq.predicates,
+		distinctProperty: pq.distinct,
+		selectColumns:    pq.columns,
+		offsetRecords:    pq.offset,
+		limitRecords:     pq.limit,
+		indexes:          doc.indexes,
+	}
+	if pq.node != "" {
+		j.From(pq.node)
+	}
+	if pq.sortBy != nil {
+		// select columns are applied after sorting
+		j.selectColumns = nil
+		j.SortBy(pq.sortBy...)
+		// predicates and distinct are already applied by SortBy
+		j.predicates, j.distinctProperty, j.selectColumns = nil, "", pq.columns
+	}
+	return j
+}
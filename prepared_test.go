@@ -0,0 +1,143 @@
+package gojsonq
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestQuery_Compile(t *testing.T) {
+	pq, err := NewQuery().
+		From("vendor.items").
+		Where("price", ">", 900).
+		OrWhere("name", "startsWith", "HP").
+		SortBy("price", "desc").
+		Select("id", "price * 2 as double").
+		Offset(1).
+		Limit(3).
+		Compile()
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+
+	doc, _ := New().FromString(jsonStr).Document()
+	out, err := pq.Run(doc)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := `[{"double":2700,"id":1},{"double":2400,"id":3},{"double":1900,"id":6}]`
+	assertJSON(t, out, expected, "run prepared query")
+
+	// the prepared query gives the same result as the query
+	qo, _ := doc.Query().
+		From("vendor.items").
+		Where("price", ">", 900).
+		OrWhere("name", "startsWith", "HP").
+		SortBy("price", "desc").
+		Select("id", "price * 2 as double").
+		Offset(1).
+		Limit(3).
+		Get()
+	assertInterface(t, qo, out, "prepared query and query results")
+
+	r, err := pq.RunR(doc)
+	if err != nil || r.Nil() {
+		t.Errorf("failed to run prepared query as result, got: %v %v", r, err)
+	}
+}
+
+func TestQuery_Compile_with_distinct_and_macro(t *testing.T) {
+	q := NewQuery(WithSeparator("->")).From("vendor->items").Distinct("price").Where("name", "startsWith", "HP")
+	pq, err := q.Compile()
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+	doc, _ := New().FromString(jsonStr).Document()
+	out, err := pq.Run(doc)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assertJSON(t, out, `[{"id":5,"key":2300,"name":"HP core i5","price":850},{"id":6,"name":"HP core i7","price":950}]`, "prepared query with custom separator and distinct")
+
+	jq := New().FromString(jsonStr)
+	jq.Macro("between", func(x, y interface{}) (bool, error) {
+		r := y.([]float64)
+		f, _ := x.(float64)
+		return f >= r[0] && f <= r[1], nil
+	})
+	doc, _ = jq.Document()
+	pq, err = doc.Query().From("vendor.items").Where("price", "between", []float64{900, 1300}).Select("id").Compile()
+	if err != nil {
+		t.Fatalf("failed to compile macro: %v", err)
+	}
+	out, _ = pq.Run(doc)
+	assertJSON(t, out, `[{"id":3},{"id":6}]`, "prepared query with macro")
+}
+
+func TestQuery_Compile_errors(t *testing.T) {
+	testCases := []struct {
+		tag   string
+		query Query
+	}{
+		{tag: "invalid operator", query: NewQuery().Where("id", "invalid", 1)},
+		{tag: "invalid sort", query: NewQuery().SortBy()},
+		{tag: "invalid offset", query: NewQuery().Offset(-1)},
+		{tag: "invalid limit", query: NewQuery().Limit(-1)},
+		{tag: "invalid option", query: NewQuery(WithSeparator(""))},
+		{tag: "unbound query", query: Query{}},
+	}
+
+	for _, tc := range testCases {
+		if _, err := tc.query.Compile(); err == nil {
+			t.Errorf("failed to catch %s", tc.tag)
+		}
+	}
+
+	pq, _ := NewQuery().Compile()
+	if _, err := pq.Run(nil); err == nil {
+		t.Error("failed to catch nil document")
+	}
+}
+
+// TestPreparedQuery_concurrent runs a prepared query against many documents in parallel,
+// run with -race to detect data races
+func TestPreparedQuery_concurrent(t *testing.T) {
+	pq, err := NewQuery().From("items").Where("qty", ">", 1).SortBy("qty", "desc").Select("sku").Compile()
+	if err != nil {
+		t.Fatalf("failed to compile: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			doc, _ := New().FromString(fmt.Sprintf(`{"items":[{"sku":"a","qty":2},{"sku":"b","qty":%d},{"sku":"c","qty":1}]}`, i+3)).Document()
+			out, err := pq.Run(doc)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			assertJSON(t, out, `[{"sku":"b"},{"sku":"a"}]`, fmt.Sprintf("payload %d", i))
+		}(i)
+	}
+	wg.Wait()
+}
+
+func Benchmark_PreparedQuery_Run(b *testing.B) {
+	pq, _ := NewQuery().From("vendor.items").Where("price", ">", 900).SortBy("price").Select("name").Compile()
+	doc, _ := New().FromString(jsonStr).Document()
+	for n := 0; n < b.N; n++ {
+		if _, err := pq.Run(doc); err != nil {
+			b.Fail()
+		}
+	}
+}
+
+func Benchmark_Query_Get(b *testing.B) {
+	doc, _ := New().FromString(jsonStr).Document()
+	for n := 0; n < b.N; n++ {
+		q := doc.Query().From("vendor.items").Where("price", ">", 900).SortBy("price").Select("name")
+		if _, err := q.Get(); err != nil {
+			b.Fail()
+		}
+	}
+}
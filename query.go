@@ -1,7 +1,6 @@
 package gojsonq
 
 import (
-	"fmt"
 	"reflect"
 	"strings"
 )
@@ -86,7 +85,7 @@ func neq(x, y interface{}) (bool, error) {
 func gt(x, y interface{}) (bool, error) {
 	xv, ok := x.(float64)
 	if !ok {
-		return false, fmt.Errorf("%v must be numeric", x)
+		return false, &TypeError{Value: x, Expected: "numeric"}
 	}
 	// if the y value is numeric (int/int8-int64/float32/float64) then convert to float64
 	if fv, ok := toFloat64(y); ok {
@@ -99,7 +98,7 @@ func gt(x, y interface{}) (bool, error) {
 func lt(x, y interface{}) (bool, error) {
 	xv, ok := x.(float64)
 	if !ok {
-		return false, fmt.Errorf("%v must be numeric", x)
+		return false, &TypeError{Value: x, Expected: "numeric"}
 	}
 	// if the y value is numeric (int/int8-int64/float32/float64) then convert to float64
 	if fv, ok := toFloat64(y); ok {
@@ -112,7 +111,7 @@ func lt(x, y interface{}) (bool, error) {
 func gte(x, y interface{}) (bool, error) {
 	xv, ok := x.(float64)
 	if !ok {
-		return false, fmt.Errorf("%v must be numeric", x)
+		return false, &TypeError{Value: x, Expected: "numeric"}
 	}
 	// if the y value is numeric (int/int8-int64/float32/float64) then convert to float64
 	if fv, ok := toFloat64(y); ok {
@@ -125,7 +124,7 @@ func gte(x, y interface{}) (bool, error) {
 func lte(x, y interface{}) (bool, error) {
 	xv, ok := x.(float64)
 	if !ok {
-		return false, fmt.Errorf("%v must be numeric", x)
+		return false, &TypeError{Value: x, Expected: "numeric"}
 	}
 	// if the y value is numeric (int/int8-int64/float32/float64) then convert to float64
 	if fv, ok := toFloat64(y); ok {
@@ -139,11 +138,11 @@ func lte(x, y interface{}) (bool, error) {
 func strStrictContains(x, y interface{}) (bool, error) {
 	xv, okX := x.(string)
 	if !okX {
-		return false, fmt.Errorf("%v must be string", x)
+		return false, &TypeError{Value: x, Expected: "string"}
 	}
 	yv, okY := y.(string)
 	if !okY {
-		return false, fmt.Errorf("%v must be string", y)
+		return false, &TypeError{Value: y, Expected: "string"}
 	}
 	return strings.Contains(xv, yv), nil
 }
@@ -153,11 +152,11 @@ func strStrictContains(x, y interface{}) (bool, error) {
 func strContains(x, y interface{}) (bool, error) {
 	xv, okX := x.(string)
 	if !okX {
-		return false, fmt.Errorf("%v must be string", x)
+		return false, &TypeError{Value: x, Expected: "string"}
 	}
 	yv, okY := y.(string)
 	if !okY {
-		return false, fmt.Errorf("%v must be string", y)
+		return false, &TypeError{Value: y, Expected: "string"}
 	}
 	return strings.Contains(strings.ToLower(xv), strings.ToLower(yv)), nil
 }
@@ -166,11 +165,11 @@ func strContains(x, y interface{}) (bool, error) {
 func strStartsWith(x, y interface{}) (bool, error) {
 	xv, okX := x.(string)
 	if !okX {
-		return false, fmt.Errorf("%v must be string", x)
+		return false, &TypeError{Value: x, Expected: "string"}
 	}
 	yv, okY := y.(string)
 	if !okY {
-		return false, fmt.Errorf("%v must be string", y)
+		return false, &TypeError{Value: y, Expected: "string"}
 	}
 	return strings.HasPrefix(xv, yv), nil
 }
@@ -179,11 +178,11 @@ func strStartsWith(x, y interface{}) (bool, error) {
 func strEndsWith(x, y interface{}) (bool, error) {
 	xv, okX := x.(string)
 	if !okX {
-		return false, fmt.Errorf("%v must be string", x)
+		return false, &TypeError{Value: x, Expected: "string"}
 	}
 	yv, okY := y.(string)
 	if !okY {
-		return false, fmt.Errorf("%v must be string", y)
+		return false, &TypeError{Value: y, Expected: "string"}
 	}
 	return strings.HasSuffix(xv, yv), nil
 }
@@ -224,7 +223,7 @@ func notIn(x, y interface{}) (bool, error) {
 func lenEq(x, y interface{}) (bool, error) {
 	yv, ok := y.(int)
 	if !ok {
-		return false, fmt.Errorf("%v must be integer", y)
+		return false, &TypeError{Value: y, Expected: "integer"}
 	}
 	xv, err := length(x)
 	if err != nil {
@@ -238,7 +237,7 @@ func lenEq(x, y interface{}) (bool, error) {
 func lenNotEq(x, y interface{}) (bool, error) {
 	yv, ok := y.(int)
 	if !ok {
-		return false, fmt.Errorf("%v must be integer", y)
+		return false, &TypeError{Value: y, Expected: "integer"}
 	}
 	xv, err := length(x)
 	if err != nil {
@@ -252,7 +251,7 @@ func lenNotEq(x, y interface{}) (bool, error) {
 func lenGt(x, y interface{}) (bool, error) {
 	yv, ok := y.(int)
 	if !ok {
-		return false, fmt.Errorf("%v must be integer", y)
+		return false, &TypeError{Value: y, Expected: "integer"}
 	}
 	xv, err := length(x)
 	if err != nil {
@@ -266,7 +265,7 @@ func lenGt(x, y interface{}) (bool, error) {
 func lenLt(x, y interface{}) (bool, error) {
 	yv, ok := y.(int)
 	if !ok {
-		return false, fmt.Errorf("%v must be integer", y)
+		return false, &TypeError{Value: y, Expected: "integer"}
 	}
 	xv, err := length(x)
 	if err != nil {
@@ -280,7 +279,7 @@ func lenLt(x, y interface{}) (bool, error) {
 func lenGte(x, y interface{}) (bool, error) {
 	yv, ok := y.(int)
 	if !ok {
-		return false, fmt.Errorf("%v must be integer", y)
+		return false, &TypeError{Value: y, Expected: "integer"}
 	}
 	xv, err := length(x)
 	if err != nil {
@@ -294,7 +293,7 @@ func lenGte(x, y interface{}) (bool, error) {
 func lenLte(x, y interface{}) (bool, error) {
 	yv, ok := y.(int)
 	if !ok {
-		return false, fmt.Errorf("%v must be integer", y)
+		return false, &TypeError{Value: y, Expected: "integer"}
 	}
 	xv, err := length(x)
 	if err != nil {
@@ -3,6 +3,7 @@ package gojsonq
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -23,15 +24,25 @@ func NewResult(v interface{}) *Result {
 
 // Result represent custom type
 type Result struct {
-	value interface{}
+	value  interface{}
+	option *option // options of the JSONQ the result comes from, nil for the default options
+	coerce bool    // accessors convert between numeric strings, numbers, booleans and times
 }
 
-// Nil check the query has result or not
+// result returns an instance of Result sharing the options of j
+func (j *JSONQ) result(v interface{}) *Result {
+	opt := j.option
+	return &Result{value: v, option: &opt, coerce: opt.coercion}
+}
+
+// Nil check the query has result or not, a nil Result has no result
 func (r *Result) Nil() bool {
-	return r.value == nil
+	return r == nil || r.value == nil
 }
 
-// As sets the value of Result to v; It does not support methods with argument available in Result
+// As sets the value of Result to v, v can be a pointer to any type: primitives and their slices
+// use the assertion methods of Result, any other type (structs, maps, nested slices, time.Time, etc)
+// is assigned following the json struct tags like Out. It does not support methods with argument available in Result
 func (r *Result) As(v interface{}) error {
 	if r.value != nil {
 		rv := reflect.ValueOf(v)
@@ -47,18 +58,18 @@ func (r *Result) As(v interface{}) error {
 		method := rv.Type().String()
 		methodMap := map[string]string{
 			"*string": "String", "*bool": "Bool", "*time.Duration": "Duration",
-			"*int": "Int", "*int8": "Int8", "*int16": "Int16", "*int32": "Int32",
-			"*uint": "Uint", "*uint8": "Uint8", "*uint16": "Uint16", "*uint32": "Uint32",
+			"*int": "Int", "*int8": "Int8", "*int16": "Int16", "*int32": "Int32", "*int64": "Int64",
+			"*uint": "Uint", "*uint8": "Uint8", "*uint16": "Uint16", "*uint32": "Uint32", "*uint64": "Uint64",
 			"*float32": "Float32", "*float64": "Float64",
 
 			"*[]string": "StringSlice", "*[]bool": "BoolSlice", "*[]time.Duration": "DurationSlice",
-			"*[]int": "IntSlice", "*[]int8": "Int8Slice", "*[]int16": "Int16Slice", "*[]int32": "Int32Slice",
-			"*[]uint": "UintSlice", "*[]uint8": "Uint8Slice", "*[]uint16": "Uint16Slice", "*[]uint32": "Uint32Slice",
+			"*[]int": "IntSlice", "*[]int8": "Int8Slice", "*[]int16": "Int16Slice", "*[]int32": "Int32Slice", "*[]int64": "Int64Slice",
+			"*[]uint": "UintSlice", "*[]uint8": "Uint8Slice", "*[]uint16": "Uint16Slice", "*[]uint32": "Uint32Slice", "*[]uint64": "Uint64Slice",
 			"*[]float32": "Float32Slice", "*[]float64": "Float64Slice",
 		}
 
 		if methodMap[method] == "" {
-			return fmt.Errorf("gojsonq: type [%T] is not available", v)
+			return convert(r.value, elm)
 		}
 
 		vv := reflect.ValueOf(r).MethodByName(methodMap[method]).Call(nil)
@@ -72,8 +83,107 @@ func (r *Result) As(v interface{}) error {
 	return nil
 }
 
+// Raw returns the underlying value of the result
+func (r *Result) Raw() interface{} {
+	return r.value
+}
+
+// Find returns the value of the exact matching path inside the result. e.g: r.Find("items.[0].name")
+func (r *Result) Find(path string) (*Result, error) {
+	separator := defaultSeparator
+	if r.option != nil {
+		separator = r.option.separator
+	}
+	v, err := getNestedValue(r.value, path, separator)
+	if err != nil {
+		return nil, withPath(err, path)
+	}
+	return &Result{value: v, option: r.option, coerce: r.coerce}, nil
+}
+
+// Query returns a new JSONQ instance to query the result, the options of the original instance are kept
+func (r *Result) Query() *JSONQ {
+	jq := New()
+	if r.option != nil {
+		jq.option = *r.option
+	}
+	return jq.FromInterface(r.value)
+}
+
+// Each calls fn for every element of a list or every value of an object in the order of the keys,
+// the iteration stops at the first error returned by fn
+func (r *Result) Each(fn func(i int, r *Result) error) error {
+	switch v := r.value.(type) {
+	case []interface{}:
+		for i, e := range v {
+			if err := fn(i, &Result{value: e, option: r.option, coerce: r.coerce}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	m, ok := objectOf(r.value)
+	if !ok {
+		return fmt.Errorf(errMessage, reflect.ValueOf(r.value).Kind())
+	}
+	for i, k := range sortedKeys(m) {
+		if err := fn(i, &Result{value: m[k], option: r.option, coerce: r.coerce}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Keys returns the sorted keys of an object
+func (r *Result) Keys() ([]string, error) {
+	m, ok := objectOf(r.value)
+	if !ok {
+		return []string{}, fmt.Errorf(errMessage, reflect.ValueOf(r.value).Kind())
+	}
+	return sortedKeys(m), nil
+}
+
+// Len returns the number of elements of a list or keys of an object, 0 for any other value
+func (r *Result) Len() int {
+	switch v := r.value.(type) {
+	case []interface{}:
+		return len(v)
+	case map[string]interface{}:
+		return len(v)
+	case map[string][]interface{}:
+		return len(v)
+	}
+	return 0
+}
+
+// Type returns the json type of the result: null, bool, number, string, array or object
+func (r *Result) Type() string {
+	return jsonType(r.value)
+}
+
+// Map assert the result to map[string]interface{}
+func (r *Result) Map() (map[string]interface{}, error) {
+	if m, ok := objectOf(r.value); ok {
+		return m, nil
+	}
+	return map[string]interface{}{}, fmt.Errorf(errMessage, reflect.ValueOf(r.value).Kind())
+}
+
+// Slice assert the result to []interface{}
+func (r *Result) Slice() ([]interface{}, error) {
+	switch v := r.value.(type) {
+	case []interface{}:
+		return v, nil
+	default:
+		return []interface{}{}, fmt.Errorf(errMessage, reflect.ValueOf(r.value).Kind())
+	}
+}
+
 // Bool assert the result to boolean value
 func (r *Result) Bool() (bool, error) {
+	if r.coerce {
+		return coerceBool(r.value)
+	}
 	switch v := r.value.(type) {
 	case bool:
 		return v, nil
@@ -84,6 +194,9 @@ func (r *Result) Bool() (bool, error) {
 
 // Time assert the result to time.Time
 func (r *Result) Time(layout string) (time.Time, error) {
+	if r.coerce {
+		return coerceTime(r.value, layout)
+	}
 	switch v := r.value.(type) {
 	case string:
 		return time.Parse(layout, v)
@@ -109,6 +222,9 @@ func (r *Result) Duration() (time.Duration, error) {
 
 // String assert the result to String
 func (r *Result) String() (string, error) {
+	if r.coerce {
+		return coerceString(r.value)
+	}
 	switch v := r.value.(type) {
 	case string:
 		return v, nil
@@ -119,6 +235,10 @@ func (r *Result) String() (string, error) {
 
 // Int assert the result to int
 func (r *Result) Int() (int, error) {
+	if r.coerce {
+		i, err := coerceInt(r.value, strconv.IntSize)
+		return int(i), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return int(v), nil
@@ -129,6 +249,10 @@ func (r *Result) Int() (int, error) {
 
 // Int8 assert the result to int8
 func (r *Result) Int8() (int8, error) {
+	if r.coerce {
+		i, err := coerceInt(r.value, 8)
+		return int8(i), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return int8(v), nil
@@ -139,6 +263,10 @@ func (r *Result) Int8() (int8, error) {
 
 // Int16 assert the result to int16
 func (r *Result) Int16() (int16, error) {
+	if r.coerce {
+		i, err := coerceInt(r.value, 16)
+		return int16(i), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return int16(v), nil
@@ -149,6 +277,10 @@ func (r *Result) Int16() (int16, error) {
 
 // Int32 assert the result to int32
 func (r *Result) Int32() (int32, error) {
+	if r.coerce {
+		i, err := coerceInt(r.value, 32)
+		return int32(i), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return int32(v), nil
@@ -159,6 +291,10 @@ func (r *Result) Int32() (int32, error) {
 
 // Int64 assert the result to int64
 func (r *Result) Int64() (int64, error) {
+	if r.coerce {
+		i, err := coerceInt(r.value, 64)
+		return int64(i), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return int64(v), nil
@@ -169,6 +305,10 @@ func (r *Result) Int64() (int64, error) {
 
 // Uint assert the result to uint
 func (r *Result) Uint() (uint, error) {
+	if r.coerce {
+		u, err := coerceUint(r.value, strconv.IntSize)
+		return uint(u), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return uint(v), nil
@@ -179,6 +319,10 @@ func (r *Result) Uint() (uint, error) {
 
 // Uint8 assert the result to uint8
 func (r *Result) Uint8() (uint8, error) {
+	if r.coerce {
+		u, err := coerceUint(r.value, 8)
+		return uint8(u), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return uint8(v), nil
@@ -189,6 +333,10 @@ func (r *Result) Uint8() (uint8, error) {
 
 // Uint16 assert the result to uint16
 func (r *Result) Uint16() (uint16, error) {
+	if r.coerce {
+		u, err := coerceUint(r.value, 16)
+		return uint16(u), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return uint16(v), nil
@@ -199,6 +347,10 @@ func (r *Result) Uint16() (uint16, error) {
 
 // Uint32 assert the result to uint32
 func (r *Result) Uint32() (uint32, error) {
+	if r.coerce {
+		u, err := coerceUint(r.value, 32)
+		return uint32(u), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return uint32(v), nil
@@ -209,6 +361,10 @@ func (r *Result) Uint32() (uint32, error) {
 
 // Uint64 assert the result to uint64
 func (r *Result) Uint64() (uint64, error) {
+	if r.coerce {
+		u, err := coerceUint(r.value, 64)
+		return uint64(u), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return uint64(v), nil
@@ -219,6 +375,10 @@ func (r *Result) Uint64() (uint64, error) {
 
 // Float32 assert the result to float32
 func (r *Result) Float32() (float32, error) {
+	if r.coerce {
+		f, err := coerceFloat(r.value, 32)
+		return float32(f), err
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return float32(v), nil
@@ -229,6 +389,9 @@ func (r *Result) Float32() (float32, error) {
 
 // Float64 assert the result to 64
 func (r *Result) Float64() (float64, error) {
+	if r.coerce {
+		return coerceFloat(r.value, 64)
+	}
 	switch v := r.value.(type) {
 	case float64:
 		return v, nil
@@ -243,6 +406,18 @@ func (r *Result) Float64() (float64, error) {
 
 // BoolSlice assert the result to []bool
 func (r *Result) BoolSlice() ([]bool, error) {
+	if r.coerce {
+		bb := make([]bool, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceBool(v)
+			if err != nil {
+				return err
+			}
+			bb = append(bb, x)
+			return nil
+		})
+		return bb, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var bb = make([]bool, 0)
@@ -259,6 +434,18 @@ func (r *Result) BoolSlice() ([]bool, error) {
 
 // TimeSlice assert the result to []time.Time
 func (r *Result) TimeSlice(layout string) ([]time.Time, error) {
+	if r.coerce {
+		tt := make([]time.Time, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceTime(v, layout)
+			if err != nil {
+				return err
+			}
+			tt = append(tt, x)
+			return nil
+		})
+		return tt, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var tt = make([]time.Time, 0)
@@ -309,6 +496,18 @@ func (r *Result) DurationSlice() ([]time.Duration, error) {
 
 // StringSlice assert the result to []string
 func (r *Result) StringSlice() ([]string, error) {
+	if r.coerce {
+		ss := make([]string, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceString(v)
+			if err != nil {
+				return err
+			}
+			ss = append(ss, x)
+			return nil
+		})
+		return ss, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var ss = make([]string, 0)
@@ -325,6 +524,18 @@ func (r *Result) StringSlice() ([]string, error) {
 
 // IntSlice assert the result to []int
 func (r *Result) IntSlice() ([]int, error) {
+	if r.coerce {
+		ii := make([]int, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceInt(v, strconv.IntSize)
+			if err != nil {
+				return err
+			}
+			ii = append(ii, int(x))
+			return nil
+		})
+		return ii, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var ii = make([]int, 0)
@@ -341,6 +552,18 @@ func (r *Result) IntSlice() ([]int, error) {
 
 // Int8Slice assert the result to []int8
 func (r *Result) Int8Slice() ([]int8, error) {
+	if r.coerce {
+		ii := make([]int8, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceInt(v, 8)
+			if err != nil {
+				return err
+			}
+			ii = append(ii, int8(x))
+			return nil
+		})
+		return ii, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var ii = make([]int8, 0)
@@ -357,6 +580,18 @@ func (r *Result) Int8Slice() ([]int8, error) {
 
 // Int16Slice assert the result to []int16
 func (r *Result) Int16Slice() ([]int16, error) {
+	if r.coerce {
+		ii := make([]int16, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceInt(v, 16)
+			if err != nil {
+				return err
+			}
+			ii = append(ii, int16(x))
+			return nil
+		})
+		return ii, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var ii = make([]int16, 0)
@@ -373,6 +608,18 @@ func (r *Result) Int16Slice() ([]int16, error) {
 
 // Int32Slice assert the result to []int32
 func (r *Result) Int32Slice() ([]int32, error) {
+	if r.coerce {
+		ii := make([]int32, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceInt(v, 32)
+			if err != nil {
+				return err
+			}
+			ii = append(ii, int32(x))
+			return nil
+		})
+		return ii, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var ii = make([]int32, 0)
@@ -389,6 +636,18 @@ func (r *Result) Int32Slice() ([]int32, error) {
 
 // Int64Slice assert the result to []int64
 func (r *Result) Int64Slice() ([]int64, error) {
+	if r.coerce {
+		ii := make([]int64, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceInt(v, 64)
+			if err != nil {
+				return err
+			}
+			ii = append(ii, x)
+			return nil
+		})
+		return ii, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var ii = make([]int64, 0)
@@ -405,6 +664,18 @@ func (r *Result) Int64Slice() ([]int64, error) {
 
 // UintSlice assert the result to []uint
 func (r *Result) UintSlice() ([]uint, error) {
+	if r.coerce {
+		uu := make([]uint, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceUint(v, strconv.IntSize)
+			if err != nil {
+				return err
+			}
+			uu = append(uu, uint(x))
+			return nil
+		})
+		return uu, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var uu = make([]uint, 0)
@@ -421,6 +692,18 @@ func (r *Result) UintSlice() ([]uint, error) {
 
 // Uint8Slice assert the result to []uint8
 func (r *Result) Uint8Slice() ([]uint8, error) {
+	if r.coerce {
+		uu := make([]uint8, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceUint(v, 8)
+			if err != nil {
+				return err
+			}
+			uu = append(uu, uint8(x))
+			return nil
+		})
+		return uu, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var uu = make([]uint8, 0)
@@ -437,6 +720,18 @@ func (r *Result) Uint8Slice() ([]uint8, error) {
 
 // Uint16Slice assert the result to []uint16
 func (r *Result) Uint16Slice() ([]uint16, error) {
+	if r.coerce {
+		uu := make([]uint16, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceUint(v, 16)
+			if err != nil {
+				return err
+			}
+			uu = append(uu, uint16(x))
+			return nil
+		})
+		return uu, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var uu = make([]uint16, 0)
@@ -453,6 +748,18 @@ func (r *Result) Uint16Slice() ([]uint16, error) {
 
 // Uint32Slice assert the result to []uint32
 func (r *Result) Uint32Slice() ([]uint32, error) {
+	if r.coerce {
+		uu := make([]uint32, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceUint(v, 32)
+			if err != nil {
+				return err
+			}
+			uu = append(uu, uint32(x))
+			return nil
+		})
+		return uu, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var uu = make([]uint32, 0)
@@ -469,6 +776,18 @@ func (r *Result) Uint32Slice() ([]uint32, error) {
 
 // Uint64Slice assert the result to []uint64
 func (r *Result) Uint64Slice() ([]uint64, error) {
+	if r.coerce {
+		uu := make([]uint64, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceUint(v, 64)
+			if err != nil {
+				return err
+			}
+			uu = append(uu, x)
+			return nil
+		})
+		return uu, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var uu = make([]uint64, 0)
@@ -485,6 +804,18 @@ func (r *Result) Uint64Slice() ([]uint64, error) {
 
 // Float32Slice assert the result to []float32
 func (r *Result) Float32Slice() ([]float32, error) {
+	if r.coerce {
+		ff := make([]float32, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceFloat(v, 32)
+			if err != nil {
+				return err
+			}
+			ff = append(ff, float32(x))
+			return nil
+		})
+		return ff, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var ff = make([]float32, 0)
@@ -501,6 +832,18 @@ func (r *Result) Float32Slice() ([]float32, error) {
 
 // Float64Slice assert the result to []float64
 func (r *Result) Float64Slice() ([]float64, error) {
+	if r.coerce {
+		ff := make([]float64, 0)
+		err := r.coerceEach(func(v interface{}) error {
+			x, err := coerceFloat(v, 64)
+			if err != nil {
+				return err
+			}
+			ff = append(ff, x)
+			return nil
+		})
+		return ff, err
+	}
 	switch v := r.value.(type) {
 	case []interface{}:
 		var ff = make([]float64, 0)
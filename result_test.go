@@ -1,6 +1,7 @@
 package gojsonq
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -897,3 +898,176 @@ func TestFloat64Slice(t *testing.T) {
 		}
 	}
 }
+
+func TestAs_arbitrary_targets(t *testing.T) {
+	type item struct {
+		ID    int      `json:"id"`
+		Name  string   `json:"name"`
+		Price float64  `json:"price"`
+		Tags  []string `json:"tags"`
+	}
+	obj := map[string]interface{}{"id": float64(1), "name": "MacBook", "price": 1350.5, "tags": []interface{}{"a"}}
+
+	var it item
+	if err := NewResult(obj).As(&it); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertInterface(t, item{ID: 1, Name: "MacBook", Price: 1350.5, Tags: []string{"a"}}, it, "As struct")
+
+	var m map[string]interface{}
+	if err := NewResult(obj).As(&m); err != nil || m["name"] != "MacBook" {
+		t.Errorf("failed As map: %v %v", m, err)
+	}
+
+	var rows []map[string]interface{}
+	if err := NewResult([]interface{}{obj, obj}).As(&rows); err != nil || len(rows) != 2 {
+		t.Errorf("failed As list of maps: %v %v", rows, err)
+	}
+
+	var nested [][]int
+	if err := NewResult([]interface{}{[]interface{}{float64(1)}, []interface{}{float64(2), float64(3)}}).As(&nested); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assertInterface(t, [][]int{{1}, {2, 3}}, nested, "As nested slices")
+
+	var tm time.Time
+	if err := NewResult("2019-01-02T15:04:05Z").As(&tm); err != nil || tm.Year() != 2019 {
+		t.Errorf("failed As time: %v %v", tm, err)
+	}
+
+	var i64 int64
+	var u64 uint64
+	if err := NewResult(float64(7)).As(&i64); err != nil || i64 != 7 {
+		t.Errorf("failed As int64: %v %v", i64, err)
+	}
+	if err := NewResult(float64(7)).As(&u64); err != nil || u64 != 7 {
+		t.Errorf("failed As uint64: %v %v", u64, err)
+	}
+
+	if err := NewResult(obj).As(&[]item{}); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch, got: %v", err)
+	}
+}
+
+func TestMap(t *testing.T) {
+	m, err := NewResult(map[string]interface{}{"a": float64(1)}).Map()
+	if err != nil || m["a"] != float64(1) {
+		t.Errorf("failed Map: %v %v", m, err)
+	}
+	m, err = NewResult(map[string][]interface{}{"g": {"x"}}).Map()
+	if err != nil || len(m) != 1 {
+		t.Errorf("failed Map of groups: %v %v", m, err)
+	}
+	if _, err := NewResult("a").Map(); err == nil {
+		t.Error("failed to catch Map error")
+	}
+}
+
+func TestSlice(t *testing.T) {
+	s, err := NewResult([]interface{}{"a", float64(1)}).Slice()
+	if err != nil || len(s) != 2 {
+		t.Errorf("failed Slice: %v %v", s, err)
+	}
+	if _, err := NewResult("a").Slice(); err == nil {
+		t.Error("failed to catch Slice error")
+	}
+}
+
+func TestRaw(t *testing.T) {
+	v := []interface{}{"a"}
+	assertInterface(t, v, NewResult(v).Raw(), "Raw")
+}
+
+func TestResult_navigation(t *testing.T) {
+	r, err := New().FromString(jsonStr).FindR("vendor")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertInterface(t, "object", r.Type(), "Type of object")
+
+	keys, err := r.Keys()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assertInterface(t, []string{"email", "items", "name", "names", "prices", "website"}, keys, "Keys")
+
+	items, err := r.Find("items")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertInterface(t, "array", items.Type(), "Type of array")
+	assertInterface(t, 7, items.Len(), "Len")
+
+	name, err := items.Find("[1].name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s, _ := name.String(); s != "MacBook Pro 15 inch retina" {
+		t.Errorf("unexpected Find result: %v", s)
+	}
+	assertInterface(t, "string", name.Type(), "Type of string")
+	assertInterface(t, 0, name.Len(), "Len of string")
+
+	if _, err := items.Find("[20]"); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Errorf("expected ErrIndexOutOfRange, got: %v", err)
+	}
+
+	count := items.Query().Where("price", ">", 1200).Count()
+	assertInterface(t, 2, count, "Query")
+
+	var ids []int
+	err = items.Each(func(i int, row *Result) error {
+		id, err := row.Find("id")
+		if err != nil {
+			return err
+		}
+		if id.Type() == "null" {
+			return nil
+		}
+		var v int
+		if err := id.As(&v); err != nil {
+			return err
+		}
+		ids = append(ids, v)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	assertInterface(t, []int{1, 2, 3, 4, 5, 6}, ids, "Each")
+
+	stop := errors.New("stop")
+	calls := 0
+	err = r.Each(func(i int, v *Result) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("Each failed to stop: %v %d", err, calls)
+	}
+	if err := name.Each(func(int, *Result) error { return nil }); err == nil {
+		t.Error("failed to catch Each error")
+	}
+	if _, err := name.Keys(); err == nil {
+		t.Error("failed to catch Keys error")
+	}
+}
+
+func TestResult_Query_keeps_options(t *testing.T) {
+	r, err := New(SetSeparator("->")).FromString(jsonStr).FindR("vendor->items")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v, err := r.Find("[0]->name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assertInterface(t, "MacBook Pro 13 inch retina", v.Raw(), "Find with separator")
+	assertInterface(t, "MacBook Pro 13 inch retina", r.Query().Find("[0]->name"), "Query with separator")
+
+	// results built with NewResult use the default options
+	v, err = NewResult(map[string]interface{}{"a": map[string]interface{}{"b": "c"}}).Find("a.b")
+	if err != nil || v.Raw() != "c" {
+		t.Errorf("failed Find with default separator: %v %v", v, err)
+	}
+}
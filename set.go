@@ -0,0 +1,85 @@
+package gojsonq
+
+import "fmt"
+
+// Union combines the list with the rows of other (a *JSONQ or []interface{}) without duplicates.
+// Rows are identified by the value of the optional key path, or by deep equality when it is not provided.
+// Set operations are applied before the Where/OrWhere clauses, which filter the combined list
+func (j *JSONQ) Union(other interface{}, keyPath ...string) *JSONQ {
+	return j.setOperation(other, keyPath, func(inA, inB bool) bool {
+		return inA || inB
+	})
+}
+
+// Intersect keeps the rows of the list which are also present in other (a *JSONQ or []interface{}).
+// Rows are identified by the value of the optional key path, or by deep equality when it is not provided
+func (j *JSONQ) Intersect(other interface{}, keyPath ...string) *JSONQ {
+	return j.setOperation(other, keyPath, func(inA, inB bool) bool {
+		return inA && inB
+	})
+}
+
+// Except keeps the rows of the list which are not present in other (a *JSONQ or []interface{}).
+// Rows are identified by the value of the optional key path, or by deep equality when it is not provided
+func (j *JSONQ) Except(other interface{}, keyPath ...string) *JSONQ {
+	return j.setOperation(other, keyPath, func(inA, inB bool) bool {
+		return inA && !inB
+	})
+}
+
+// setOperation adds a stage which keeps the distinct rows of both lists satisfying keep
+func (j *JSONQ) setOperation(other interface{}, keyPath []string, keep func(inA, inB bool) bool) *JSONQ {
+	if len(keyPath) > 1 {
+		return j.addError(fmt.Errorf("set operation accepts only one key path"))
+	}
+	var rows []interface{}
+	switch o := other.(type) {
+	case *JSONQ:
+		v := o.Get()
+		j.errors = append(j.errors, o.Errors()...)
+		list, ok := v.([]interface{})
+		if !ok {
+			return j.addError(fmt.Errorf("invalid set operation source type [%T]", v))
+		}
+		rows = list
+	case []interface{}:
+		rows = o
+	default:
+		return j.addError(fmt.Errorf("invalid set operation source type [%T]", other))
+	}
+
+	j.stages = append(j.stages, func(j *JSONQ, aa []interface{}) []interface{} {
+		identity := func(v interface{}) string {
+			if len(keyPath) > 0 {
+				if kv, err := getNestedValue(v, keyPath[0], j.option.separator); err == nil {
+					return hashKey(kv)
+				}
+			}
+			return hashKey(v)
+		}
+
+		inB := make(map[string]bool, len(rows))
+		for _, r := range rows {
+			inB[identity(r)] = true
+		}
+
+		result := make([]interface{}, 0)
+		seen := make(map[string]bool, len(aa))
+		for _, a := range aa {
+			id := identity(a)
+			if !seen[id] && keep(true, inB[id]) {
+				result = append(result, a)
+			}
+			seen[id] = true
+		}
+		for _, r := range rows {
+			id := identity(r)
+			if !seen[id] && keep(false, true) {
+				result = append(result, r)
+			}
+			seen[id] = true
+		}
+		return result
+	})
+	return j
+}
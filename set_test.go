@@ -0,0 +1,108 @@
+package gojsonq
+
+import (
+	"testing"
+)
+
+const (
+	jsonStrExportA = `[{"sku":"a","qty":1},{"sku":"b","qty":2},{"sku":"c","qty":3},{"sku":"a","qty":1}]`
+	jsonStrExportB = `[{"sku":"b","qty":2},{"sku":"c","qty":5},{"sku":"d","qty":4}]`
+)
+
+func TestJSONQ_Union(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		keyPath  []string
+		expected string
+	}{
+		{
+			tag:      "union using deep equality",
+			expected: `[{"qty":1,"sku":"a"},{"qty":2,"sku":"b"},{"qty":3,"sku":"c"},{"qty":5,"sku":"c"},{"qty":4,"sku":"d"}]`,
+		},
+		{
+			tag:      "union using key path",
+			keyPath:  []string{"sku"},
+			expected: `[{"qty":1,"sku":"a"},{"qty":2,"sku":"b"},{"qty":3,"sku":"c"},{"qty":4,"sku":"d"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		jq := New().FromString(jsonStrExportA).Union(New().FromString(jsonStrExportB), tc.keyPath...)
+		assertJSON(t, jq.Get(), tc.expected, tc.tag)
+	}
+}
+
+func TestJSONQ_Intersect(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		keyPath  []string
+		expected string
+	}{
+		{
+			tag:      "intersect using deep equality",
+			expected: `[{"qty":2,"sku":"b"}]`,
+		},
+		{
+			tag:      "intersect using key path",
+			keyPath:  []string{"sku"},
+			expected: `[{"qty":2,"sku":"b"},{"qty":3,"sku":"c"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		jq := New().FromString(jsonStrExportA).Intersect(New().FromString(jsonStrExportB), tc.keyPath...)
+		assertJSON(t, jq.Get(), tc.expected, tc.tag)
+	}
+}
+
+func TestJSONQ_Except(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		keyPath  []string
+		expected string
+	}{
+		{
+			tag:      "except using deep equality",
+			expected: `[{"qty":1,"sku":"a"},{"qty":3,"sku":"c"}]`,
+		},
+		{
+			tag:      "except using key path",
+			keyPath:  []string{"sku"},
+			expected: `[{"qty":1,"sku":"a"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		jq := New().FromString(jsonStrExportA).Except(New().FromString(jsonStrExportB), tc.keyPath...)
+		assertJSON(t, jq.Get(), tc.expected, tc.tag)
+	}
+}
+
+func TestJSONQ_set_operation_with_slice_and_where(t *testing.T) {
+	other := []interface{}{float64(1), float64(3), "x"}
+	jq := New().FromString(`{"ids":[1,2,3,4]}`).From("ids").Except(other)
+	assertJSON(t, jq.Get(), `[2,4]`, "except with []interface{}")
+
+	out := New().FromString(jsonStrExportA).
+		Union(New().FromString(jsonStrExportB), "sku").
+		Where("qty", ">", 1).
+		Pluck("sku")
+	assertJSON(t, out, `["b","c","d"]`, "where filters the combined list")
+}
+
+func TestJSONQ_set_operation_with_invalid_source(t *testing.T) {
+	jq := New().FromString(jsonStrExportA).Union(map[string]interface{}{})
+	if jq.Error() == nil {
+		t.Error("failed to catch invalid source type")
+	}
+
+	jq = New().FromString(jsonStrExportA).Intersect(New().FromString(`{"sku":"a"}`))
+	if jq.Error() == nil {
+		t.Error("failed to catch invalid source content")
+	}
+
+	jq = New().FromString(jsonStrExportA).Except([]interface{}{}, "a", "b")
+	if jq.Error() == nil {
+		t.Error("failed to catch multiple key paths")
+	}
+}
@@ -0,0 +1,91 @@
+package gojsonq
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// streamFlushRows is the number of rows written between two flushes of WriteStream
+const streamFlushRows = 256
+
+// flusher is implemented by the writers able to send the buffered data, e.g: http.ResponseWriter
+type flusher interface {
+	Flush()
+}
+
+// WriteStream writes the result of the query to w as a JSON array whose elements are encoded as soon
+// as they pass the filters, without building the result in memory. The data is flushed to w (and w
+// is flushed when it implements Flush like http.ResponseWriter) every few rows. With NDJSONEncoder
+// as encoder, a row is written per line instead. A result which is not a list is written as a single value.
+// It returns the error writing to w, otherwise the error of the context or the first error of the query
+func (j *JSONQ) WriteStream(w io.Writer) error {
+	_, ndjson := j.option.encoder.(*NDJSONEncoder)
+	bw := bufio.NewWriter(w)
+	flush := func() error {
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+		return nil
+	}
+
+	it := j.Iter()
+	err := func() error {
+		if it.single {
+			return writeJSONLine(bw, j.jsonContent)
+		}
+		if !ndjson {
+			if err := bw.WriteByte('['); err != nil {
+				return err
+			}
+		}
+		for n := 0; it.Next(); n++ {
+			data, err := json.Marshal(it.value)
+			if err != nil {
+				return err
+			}
+			switch {
+			case ndjson:
+				data = append(data, '\n')
+			case n > 0:
+				if err := bw.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			if _, err := bw.Write(data); err != nil {
+				return err
+			}
+			if (n+1)%streamFlushRows == 0 {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if !ndjson {
+			_, err := bw.WriteString("]\n")
+			return err
+		}
+		return nil
+	}()
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		j.addError(err)
+		return err
+	}
+	return it.Err()
+}
+
+// writeJSONLine writes v as a single line JSON
+func writeJSONLine(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
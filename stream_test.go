@@ -0,0 +1,117 @@
+package gojsonq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONQ_WriteStream(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		jq       *JSONQ
+		expected string
+	}{
+		{
+			tag:      "filtered list",
+			jq:       New().FromString(jsonStr).From("vendor.items").Where("price", ">", 1200).Select("id", "name"),
+			expected: `[{"id":1,"name":"MacBook Pro 13 inch retina"},{"id":2,"name":"MacBook Pro 15 inch retina"}]` + "\n",
+		},
+		{
+			tag:      "offset and limit",
+			jq:       New().FromString(jsonStr).From("vendor.prices").Offset(1).Limit(2),
+			expected: "[2100,1200]\n",
+		},
+		{
+			tag:      "empty result",
+			jq:       New().FromString(jsonStr).From("vendor.items").Where("price", ">", 10000),
+			expected: "[]\n",
+		},
+		{
+			tag:      "single value",
+			jq:       New().FromString(jsonStr).From("vendor.name"),
+			expected: "\"Star Trek\"\n",
+		},
+		{
+			tag:      "ndjson",
+			jq:       New(WithEncoder(&NDJSONEncoder{})).FromString(jsonStr).From("vendor.items").WhereIn("id", []int{3, 4}).Select("id"),
+			expected: "{\"id\":3}\n{\"id\":4}\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		var b bytes.Buffer
+		if err := tc.jq.WriteStream(&b); err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.tag, err)
+		}
+		assertInterface(t, tc.expected, b.String(), tc.tag)
+	}
+}
+
+func TestJSONQ_WriteStream_same_as_Writer(t *testing.T) {
+	var stream, writer bytes.Buffer
+	New().FromString(jsonStr).From("vendor.items").WhereNotNil("id").WriteStream(&stream)
+	New().FromString(jsonStr).From("vendor.items").WhereNotNil("id").Writer(&writer)
+	assertInterface(t, writer.String(), stream.String(), "WriteStream and Writer output")
+}
+
+// flushCounter counts the flushes and the rows written before the first flush
+type flushCounter struct {
+	bytes.Buffer
+	flushes  int
+	firstLen int
+}
+
+func (f *flushCounter) Flush() {
+	if f.flushes == 0 {
+		f.firstLen = f.Len()
+	}
+	f.flushes++
+}
+
+func TestJSONQ_WriteStream_flushes(t *testing.T) {
+	var w flushCounter
+	if err := New().FromString(benchmarkItems(1000)).From("items").WriteStream(&w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 1000 rows are flushed 3 times every 256 rows then once at the end
+	if w.flushes != 4 {
+		t.Errorf("expected 4 flushes, got %d", w.flushes)
+	}
+	if w.firstLen == 0 || w.firstLen >= w.Len() {
+		t.Errorf("expected a partial first flush, got %d of %d bytes", w.firstLen, w.Len())
+	}
+}
+
+// failingWriter fails after accepting n bytes
+type failingWriter struct {
+	n int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > f.n {
+		return 0, errors.New("write failed")
+	}
+	f.n -= len(p)
+	return len(p), nil
+}
+
+func TestJSONQ_WriteStream_errors(t *testing.T) {
+	jq := New().FromString(benchmarkItems(1000)).From("items")
+	if err := jq.WriteStream(&failingWriter{n: 10}); err == nil || jq.Error() == nil {
+		t.Errorf("failed to catch write error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var b bytes.Buffer
+	jq = New().FromString(benchmarkItems(1000)).From("items").WithContext(ctx)
+	if err := jq.WriteStream(&b); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got: %v", err)
+	}
+	if !strings.HasSuffix(b.String(), "]\n") {
+		t.Errorf("expected a closed array, got: %q", b.String())
+	}
+}
@@ -0,0 +1,95 @@
+package gojsonq
+
+import "time"
+
+// Trace describes the statistics of the where clauses recorded with WithTracing.
+// The where clauses scan the list once per query, e.g: SortBy followed by Get, statistics accumulate
+// over the scans until Reset
+type Trace struct {
+	Rows     int                // number of rows scanned
+	Matched  int                // number of rows matched
+	Duration time.Duration      // time spent scanning the rows
+	Groups   [][]PredicateTrace // statistics of the where clauses, one group per OrWhere
+}
+
+// PredicateTrace describes the statistics of a where clause
+type PredicateTrace struct {
+	Key      string
+	Operator string
+	Value    interface{}
+	Passed   int           // number of rows satisfying the clause
+	Failed   int           // number of rows not satisfying the clause
+	Errors   int           // number of rows the clause failed to evaluate, e.g: missing key
+	Duration time.Duration // time spent evaluating the clause
+}
+
+// Trace returns the statistics of the where clauses recorded while running the query,
+// nil unless the JSONQ instance is created WithTracing
+func (j *JSONQ) Trace() *Trace {
+	return j.trace
+}
+
+// traceScan records the scan of rows, the returned func records the duration of the scan
+func (j *JSONQ) traceScan(rows int, groups [][]predicate, start time.Time) func() {
+	if j.trace == nil || !sameShape(j.trace.Groups, groups) {
+		j.trace = &Trace{Groups: make([][]PredicateTrace, len(groups))}
+		for gi, group := range groups {
+			j.trace.Groups[gi] = make([]PredicateTrace, len(group))
+			for pi, p := range group {
+				j.trace.Groups[gi][pi] = PredicateTrace{Key: p.key, Operator: p.operator, Value: p.value}
+			}
+		}
+	}
+	j.trace.Rows += rows
+	return func() {
+		j.trace.Duration += time.Since(start)
+	}
+}
+
+// tracePredicate records the evaluation of the where clause at position pi of group gi
+func (j *JSONQ) tracePredicate(gi, pi int, passed bool, err error, start time.Time) {
+	if j.trace == nil {
+		return
+	}
+	pt := &j.trace.Groups[gi][pi]
+	switch {
+	case err != nil:
+		pt.Errors++
+	case passed:
+		pt.Passed++
+	default:
+		pt.Failed++
+	}
+	pt.Duration += time.Since(start)
+}
+
+// sameShape checks whether the recorded statistics belong to the groups
+func sameShape(tt [][]PredicateTrace, groups [][]predicate) bool {
+	if len(tt) != len(groups) {
+		return false
+	}
+	for i := range tt {
+		if len(tt[i]) != len(groups[i]) {
+			return false
+		}
+		for k := range tt[i] {
+			if tt[i][k].Key != groups[i][k].key || tt[i][k].Operator != groups[i][k].operator {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// merge adds the statistics of the where clauses recorded by a worker
+func (t *Trace) merge(w *Trace) {
+	for gi, group := range w.Groups {
+		for pi, pt := range group {
+			dst := &t.Groups[gi][pi]
+			dst.Passed += pt.Passed
+			dst.Failed += pt.Failed
+			dst.Errors += pt.Errors
+			dst.Duration += pt.Duration
+		}
+	}
+}
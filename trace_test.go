@@ -0,0 +1,91 @@
+package gojsonq
+
+import "testing"
+
+func TestJSONQ_Trace(t *testing.T) {
+	jq := New(WithTracing()).FromString(jsonStr).
+		From("vendor.items").
+		Where("price", ">", 1000).
+		Where("name", "startsWith", "HP").
+		OrWhere("key", "=", 2300)
+	assertJSON(t, jq.Pluck("id"), `[5]`, "traced query")
+
+	tr := jq.Trace()
+	if tr == nil {
+		t.Fatal("expected trace")
+	}
+	if tr.Rows != 7 || tr.Matched != 1 {
+		t.Errorf("expected 7 rows and 1 match, got: %d and %d", tr.Rows, tr.Matched)
+	}
+
+	testCases := []struct {
+		tag                    string
+		pt                     PredicateTrace
+		passed, failed, errors int
+	}{
+		{tag: "price", pt: tr.Groups[0][0], passed: 3, failed: 4},
+		{tag: "name", pt: tr.Groups[0][1], passed: 3, failed: 4},
+		{tag: "key", pt: tr.Groups[1][0], passed: 1, errors: 6},
+	}
+	for _, tc := range testCases {
+		if tc.pt.Passed != tc.passed || tc.pt.Failed != tc.failed || tc.pt.Errors != tc.errors {
+			t.Errorf("tag: %s, expected %d/%d/%d got: %d/%d/%d", tc.tag, tc.passed, tc.failed, tc.errors, tc.pt.Passed, tc.pt.Failed, tc.pt.Errors)
+		}
+	}
+	if tr.Groups[0][1].Key != "name" || tr.Groups[0][1].Operator != "startsWith" || tr.Groups[0][1].Value != "HP" {
+		t.Errorf("unexpected where clause: %+v", tr.Groups[0][1])
+	}
+
+	jq.Reset()
+	if jq.Trace() != nil {
+		t.Error("expected Reset to clear the trace")
+	}
+}
+
+func TestJSONQ_Trace_disabled(t *testing.T) {
+	jq := New().FromString(jsonStr).From("vendor.items").WhereEqual("id", 1)
+	jq.Get()
+	if jq.Trace() != nil {
+		t.Error("expected no trace without WithTracing")
+	}
+}
+
+func TestJSONQ_Trace_scans_once(t *testing.T) {
+	jq := New(WithTracing()).FromString(jsonStr).From("vendor.items").Where("price", ">", 1000)
+	out := jq.SortBy("price").Get()
+	assertJSON(t, out, `[{"id":3,"name":"Sony VAIO","price":1200},{"id":1,"name":"MacBook Pro 13 inch retina","price":1350},{"id":2,"name":"MacBook Pro 15 inch retina","price":1700}]`, "traced sorted query")
+	// the rows matched before sorting are not filtered again
+	if tr := jq.Trace(); tr.Rows != 7 || tr.Matched != 3 || tr.Groups[0][0].Passed != 3 || tr.Groups[0][0].Failed != 4 {
+		t.Errorf("unexpected trace: %+v", tr)
+	}
+
+	// a where clause added later filters the sorted rows again
+	assertJSON(t, jq.WhereEqual("id", 1).Pluck("id"), `[1]`, "where clause added after sorting")
+}
+
+func TestJSONQ_Trace_iterator(t *testing.T) {
+	testCases := []struct {
+		tag                   string
+		run                   func(jq *JSONQ)
+		rows, matched, passed int
+	}{
+		{tag: "limit", run: func(jq *JSONQ) { jq.Limit(2).Get() }, rows: 2, matched: 2, passed: 2},
+		{tag: "first", run: func(jq *JSONQ) { jq.First() }, rows: 1, matched: 1, passed: 1},
+		{tag: "sort and limit", run: func(jq *JSONQ) { jq.SortBy("price").Limit(2).Get() }, rows: 7, matched: 4, passed: 4},
+	}
+	for _, tc := range testCases {
+		jq := New(WithTracing()).FromString(jsonStr).From("vendor.items").Where("price", ">", 900)
+		tc.run(jq)
+		tr := jq.Trace()
+		if tr == nil {
+			t.Errorf("tag: %s, expected trace", tc.tag)
+			continue
+		}
+		if tr.Rows != tc.rows || tr.Matched != tc.matched || tr.Groups[0][0].Passed != tc.passed {
+			t.Errorf("tag: %s, expected %d/%d/%d got: %+v", tc.tag, tc.rows, tc.matched, tc.passed, tr)
+		}
+		if tr.Duration <= 0 {
+			t.Errorf("tag: %s, expected the scan duration", tc.tag)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package gojsonq
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// YAMLEncoder encodes the result as a YAML document, the keys are sorted
+type YAMLEncoder struct {
+	Indent int // number of spaces of each level, default and minimum 2
+}
+
+// Encode encodes the result as YAML
+func (e *YAMLEncoder) Encode(w io.Writer, v interface{}) error {
+	indent := e.Indent
+	if indent < 2 {
+		indent = 2
+	}
+	y := &yamlWriter{w: bufio.NewWriter(w), indent: indent}
+	y.node(v, 0)
+	return y.w.Flush()
+}
+
+// yamlWriter emits the block style YAML of a decoded json value
+type yamlWriter struct {
+	w      *bufio.Writer
+	indent int
+}
+
+// node writes v on its own lines at the level of depth
+func (y *yamlWriter) node(v interface{}, depth int) {
+	if isEmptyCollection(v) || !isCollection(v) {
+		y.line(depth, yamlScalar(v))
+		return
+	}
+	y.collection(v, depth, false)
+}
+
+// collection writes the entries of a non empty list or object, inline is true when the first
+// entry continues the current line, e.g: after the dash of a list item
+func (y *yamlWriter) collection(v interface{}, depth int, inline bool) {
+	first := true
+	pad := func() {
+		if !(first && inline) {
+			y.w.WriteString(strings.Repeat(" ", depth*y.indent))
+		}
+		first = false
+	}
+	if aa, ok := v.([]interface{}); ok {
+		for _, a := range aa {
+			pad()
+			if isCollection(a) && !isEmptyCollection(a) {
+				// the dash is padded so that the first entry is aligned with the following ones
+				y.w.WriteString("-" + strings.Repeat(" ", y.indent-1))
+				y.collection(a, depth+1, true)
+				continue
+			}
+			y.w.WriteString("- ")
+			y.w.WriteString(yamlScalar(a))
+			y.w.WriteByte('\n')
+		}
+		return
+	}
+	m, _ := objectOf(v)
+	for _, k := range sortedKeys(m) {
+		pad()
+		y.w.WriteString(yamlString(k))
+		y.w.WriteByte(':')
+		mv := m[k]
+		if isCollection(mv) && !isEmptyCollection(mv) {
+			y.w.WriteByte('\n')
+			y.collection(mv, depth+1, false)
+			continue
+		}
+		y.w.WriteByte(' ')
+		y.w.WriteString(yamlScalar(mv))
+		y.w.WriteByte('\n')
+	}
+}
+
+// line writes s indented at the level of depth
+func (y *yamlWriter) line(depth int, s string) {
+	y.w.WriteString(strings.Repeat(" ", depth*y.indent))
+	y.w.WriteString(s)
+	y.w.WriteByte('\n')
+}
+
+// isCollection checks whether v is a list or an object
+func isCollection(v interface{}) bool {
+	switch v.(type) {
+	case []interface{}, map[string]interface{}, map[string][]interface{}:
+		return true
+	}
+	return false
+}
+
+// isEmptyCollection checks whether v is an empty list or object
+func isEmptyCollection(v interface{}) bool {
+	switch x := v.(type) {
+	case []interface{}:
+		return len(x) == 0
+	case map[string]interface{}:
+		return len(x) == 0
+	case map[string][]interface{}:
+		return len(x) == 0
+	}
+	return false
+}
+
+// yamlScalar formats a scalar or an empty collection in flow style
+func yamlScalar(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(x)
+	case float64:
+		return formatNumber(x)
+	case string:
+		return yamlString(x)
+	case json.Number:
+		return string(x)
+	case []interface{}:
+		return "[]"
+	case map[string]interface{}, map[string][]interface{}:
+		return "{}"
+	}
+	return yamlString(csvValue(v))
+}
+
+// yamlString quotes s when it would not be read back as the same plain string
+func yamlString(s string) string {
+	if s == "" || yamlReserved(s) || strings.TrimSpace(s) != s ||
+		strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f || r == '\u0085' || r == '\u2028' || r == '\u2029' || r == '\ufeff' {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// yamlReserved checks whether the plain s would be read as null, a boolean or a number
+func yamlReserved(s string) bool {
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n", ".inf", "-.inf", "+.inf", ".nan":
+		return true
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	if err == nil {
+		return true
+	}
+	if _, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return true
+	}
+	return false
+}
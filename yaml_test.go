@@ -0,0 +1,111 @@
+package gojsonq
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestYAMLEncoder(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		json     string
+		indent   int
+		expected string
+	}{
+		{
+			tag:  "nested document",
+			json: `{"name":"Star Trek","items":[{"id":1,"tags":["a","b"],"dims":[[1,2],[]]},{"id":null,"meta":{}}],"empty":[],"ok":true,"ratio":0.5}`,
+			expected: `empty: []
+items:
+  - dims:
+      - - 1
+        - 2
+      - []
+    id: 1
+    tags:
+      - a
+      - b
+  - id: null
+    meta: {}
+name: Star Trek
+ok: true
+ratio: 0.5
+`,
+		},
+		{
+			tag:    "indent",
+			json:   `{"a":{"b":["c"]}}`,
+			indent: 4,
+			expected: `a:
+    b:
+        - c
+`,
+		},
+		{
+			tag:    "indent of a list of objects",
+			json:   `{"items":[{"a":1,"b":2},{"c":[[1,2],{"d":3,"e":4}]}]}`,
+			indent: 4,
+			expected: `items:
+    -   a: 1
+        b: 2
+    -   c:
+            -   - 1
+                - 2
+            -   d: 3
+                e: 4
+`,
+		},
+		{
+			tag:    "minimum indent",
+			json:   `[{"a":1,"b":2}]`,
+			indent: 1,
+			expected: `- a: 1
+  b: 2
+`,
+		},
+		{
+			tag:  "quoted strings",
+			json: `["", "true", "No", "12", "0x1F", "1e3", "- a", "a: b", "a #b", " x", "line\nbreak", "key:", "plain text", "null", "~", "@x"]`,
+			expected: `- ""
+- "true"
+- "No"
+- "12"
+- "0x1F"
+- "1e3"
+- "- a"
+- "a: b"
+- "a #b"
+- " x"
+- "line\nbreak"
+- "key:"
+- plain text
+- "null"
+- "~"
+- "@x"
+`,
+		},
+		{
+			tag:      "quoted keys",
+			json:     `{"true":1,"a b":2,"":3}`,
+			expected: "\"\": 3\na b: 2\n\"true\": 1\n",
+		},
+		{
+			tag:      "scalar",
+			json:     `{"a":1}`,
+			expected: "1\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		var b bytes.Buffer
+		jq := New(WithEncoder(&YAMLEncoder{Indent: tc.indent})).FromString(tc.json)
+		if tc.tag == "scalar" {
+			jq.From("a")
+		}
+		jq.Writer(&b)
+		if jq.Error() != nil {
+			t.Errorf("%s: unexpected error: %v", tc.tag, jq.Error())
+		}
+		assertInterface(t, tc.expected, b.String(), tc.tag)
+	}
+}